@@ -1,17 +1,17 @@
 module github.com/go-generics-playground/generics
 
-go 1.22
+go 1.24
 
 require (
 	github.com/go-faker/faker/v4 v4.4.1
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842
+	golang.org/x/text v0.14.0
 	google.golang.org/protobuf v1.34.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
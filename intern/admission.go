@@ -0,0 +1,177 @@
+package intern
+
+import (
+	"log"
+	"math"
+)
+
+// AdmissionStats counts the decisions of an AdmissionIntern
+type AdmissionStats struct {
+	// Seen is the number of values that were not interned yet when inserted
+	Seen uint64
+	// Admitted is the number of values that reached the threshold & were interned
+	Admitted uint64
+	// Rejected is the number of values returned as-is because they were below the threshold
+	Rejected uint64
+	// Resets is the number of times the frequency counters were aged at the end of a window
+	Resets uint64
+}
+
+// AdmissionIntern is a GenericIntern that only stores values after they have been seen `threshold` times within a window,
+// so one-off values from a long-tail stream do not fill the table.
+// Until a value is admitted, Deduplicate returns the input unchanged & Insert returns 0 (which is never a valid unique id).
+type AdmissionIntern[T comparable] interface {
+	GenericIntern[T]
+
+	// AdmissionStats returns the admission counters
+	AdmissionStats() AdmissionStats
+}
+
+var _ AdmissionIntern[int] = &admissionIntern[int]{}
+
+// admissionIntern implements the AdmissionIntern interface with a TinyLFU style frequency estimate:
+// a doorkeeper bloom filter absorbs the first occurrence of each value, & a count-min sketch counts the rest.
+// Every window observations the sketch is halved & the doorkeeper cleared, so old frequencies fade out.
+type admissionIntern[T comparable] struct {
+	intern    *genericIntern[T]
+	hash      func(T) uint64
+	threshold uint64
+	window    uint64
+
+	sketch     [sketchDepth][]uint8
+	doorkeeper []uint64
+	observed   uint64
+	stats      AdmissionStats
+}
+
+const sketchDepth = 4
+
+// NewAdmission creates a new AdmissionIntern[T] instance admitting values seen at least threshold times within window observations.
+// The hash must return the same hash for equal values. threshold must be in [1, 255].
+func NewAdmission[T comparable](threshold, window int, hash func(T) uint64) AdmissionIntern[T] {
+	if nil == hash {
+		log.Panic("hash is required for NewAdmission")
+	}
+	if threshold < 1 || threshold > math.MaxUint8 {
+		log.Panicf("threshold(%d) must be in [1, %d]", threshold, math.MaxUint8)
+	}
+	if window < threshold {
+		log.Panicf("window(%d) must be >= threshold(%d)", window, threshold)
+	}
+
+	width := nextPowerOfTwo(uint64(window))
+	output := &admissionIntern[T]{
+		intern:     New[T]().(*genericIntern[T]),
+		hash:       hash,
+		threshold:  uint64(threshold),
+		window:     uint64(window),
+		doorkeeper: make([]uint64, width/8+1),
+	}
+	for row := range output.sketch {
+		output.sketch[row] = make([]uint8, width)
+	}
+	return output
+}
+
+func (i *admissionIntern[T]) Deduplicate(input T) T {
+	if uniqueID := i.Insert(input); uniqueID != 0 {
+		return i.intern.values[uniqueID]
+	}
+	return input
+}
+
+func (i *admissionIntern[T]) Insert(input T) uint64 {
+	if _, ok := i.intern.keys[input]; ok {
+		return i.intern.Insert(input)
+	}
+
+	i.stats.Seen++
+	if i.observe(i.hash(input)) < i.threshold {
+		i.stats.Rejected++
+		return 0
+	}
+	i.stats.Admitted++
+	return i.intern.Insert(input)
+}
+
+func (i *admissionIntern[T]) Value(uniqueID uint64) (T, bool) {
+	return i.intern.Value(uniqueID)
+}
+
+func (i *admissionIntern[T]) Len() int {
+	return i.intern.Len()
+}
+
+// Clear deletes the interned values & the frequency counters, the admission stats are kept.
+func (i *admissionIntern[T]) Clear() {
+	i.intern.Clear()
+	for row := range i.sketch {
+		clear(i.sketch[row])
+	}
+	clear(i.doorkeeper)
+	i.observed = 0
+}
+
+func (i *admissionIntern[T]) AdmissionStats() AdmissionStats {
+	return i.stats
+}
+
+// Stats returns the stats of the underlying table, only admitted values are counted.
+func (i *admissionIntern[T]) Stats() Stats {
+	return i.intern.Stats()
+}
+
+// observe records an occurrence of the hash & returns its estimated frequency in the current window
+func (i *admissionIntern[T]) observe(hash uint64) uint64 {
+	count := uint64(1)
+	if !i.doorkeeperAdd(hash) {
+		count += i.sketchIncrement(hash)
+	}
+
+	i.observed++
+	if i.observed >= i.window {
+		i.age()
+	}
+	return count
+}
+
+// doorkeeperAdd adds the hash to the bloom filter, & returns true if it was not present
+func (i *admissionIntern[T]) doorkeeperAdd(hash uint64) bool {
+	bits := uint64(len(i.doorkeeper)) * 64
+	added := false
+	for _, seed := range [...]uint64{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9} {
+		bit := mix(hash, seed) % bits
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if i.doorkeeper[word]&mask == 0 {
+			i.doorkeeper[word] |= mask
+			added = true
+		}
+	}
+	return added
+}
+
+// sketchIncrement increments the count-min sketch counters of the hash, & returns the new estimate
+func (i *admissionIntern[T]) sketchIncrement(hash uint64) uint64 {
+	estimate := uint64(math.MaxUint8)
+	for row := range i.sketch {
+		counters := i.sketch[row]
+		index := mix(hash, uint64(row+1)) & uint64(len(counters)-1)
+		if counters[index] < math.MaxUint8 {
+			counters[index]++
+		}
+		estimate = min(estimate, uint64(counters[index]))
+	}
+	return estimate
+}
+
+// age halves the sketch & clears the doorkeeper at the end of a window
+func (i *admissionIntern[T]) age() {
+	for row := range i.sketch {
+		for index := range i.sketch[row] {
+			i.sketch[row][index] >>= 1
+		}
+	}
+	clear(i.doorkeeper)
+	i.observed = 0
+	i.stats.Resets++
+}
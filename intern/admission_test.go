@@ -0,0 +1,69 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestAdmissionIntern(t *testing.T) {
+	i := NewAdmission[string](3, 1_000, fnvHash)
+
+	first := strings.Clone("value")
+	require.Zero(t, i.Insert(first))
+	second := strings.Clone("value")
+	require.Same(t, unsafe.StringData(second), unsafe.StringData(i.Deduplicate(second)))
+	require.Zero(t, i.Len())
+
+	index := i.Insert("value")
+	require.NotZero(t, index)
+	require.EqualValues(t, 1, i.Len())
+	require.EqualValues(t, index, i.Insert("value"))
+
+	output, ok := i.Value(index)
+	require.True(t, ok)
+	require.EqualValues(t, "value", output)
+	require.EqualValues(t, "value", i.Deduplicate(strings.Clone("value")))
+
+	for index := 0; index < 1_00; index++ {
+		input := strconv.Itoa(index)
+		require.EqualValues(t, input, i.Deduplicate(input))
+	}
+	require.EqualValues(t, 1, i.Len())
+
+	stats := i.AdmissionStats()
+	require.EqualValues(t, 1, stats.Admitted)
+	require.EqualValues(t, stats.Seen, stats.Admitted+stats.Rejected)
+	require.EqualValues(t, 1_02, stats.Rejected)
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	require.Zero(t, i.Insert("value"))
+}
+
+func TestAdmissionIntern_window(t *testing.T) {
+	i := NewAdmission[string](2, 4, fnvHash)
+
+	require.Zero(t, i.Insert("value"))
+	for _, input := range []string{"a", "b", "c"} {
+		require.Zero(t, i.Insert(input))
+	}
+	require.EqualValues(t, 1, i.AdmissionStats().Resets)
+
+	// The first occurrence was forgotten with the doorkeeper at the end of the window
+	require.Zero(t, i.Insert("value"))
+	require.NotZero(t, i.Insert("value"))
+
+	admitAll := NewAdmission[string](1, 1, fnvHash)
+	require.NotZero(t, admitAll.Insert("value"))
+}
+
+// fnvHash is a deterministic hash, so doorkeeper & sketch false positives are the same on every run
+func fnvHash(input string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(input))
+	return hash.Sum64()
+}
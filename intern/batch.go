@@ -0,0 +1,111 @@
+package intern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownID is returned when a unique id is not present in the intern table
+var ErrUnknownID = errors.New("intern: unknown unique id")
+
+// BatchIntern is implemented by intern tables with native batch operations, e.g. to lock once per batch.
+// Use the package level InsertAll, Values & DeduplicateAll functions to fall back to one value at a time for other tables.
+type BatchIntern[T comparable] interface {
+	// InsertAll inserts every input & appends their unique ids to dst, in order.
+	InsertAll(inputs []T, dst []uint64) []uint64
+
+	// Values appends the interned value of every unique id to dst, in order.
+	// If a unique id is not present, then the values resolved so far & an ErrUnknownID error are returned.
+	Values(uniqueIDs []uint64, dst []T) ([]T, error)
+
+	// DeduplicateAll replaces every input with its interned value, in place.
+	DeduplicateAll(inputs []T)
+}
+
+var _ BatchIntern[int] = &genericIntern[int]{}
+var _ BatchIntern[int] = &safeGeneric[int]{}
+
+// InsertAll inserts every input into the intern table & appends their unique ids to dst, in order.
+func InsertAll[T comparable](intern GenericIntern[T], inputs []T, dst []uint64) []uint64 {
+	if batch, ok := intern.(BatchIntern[T]); ok {
+		return batch.InsertAll(inputs, dst)
+	}
+	return insertAll(intern, inputs, dst)
+}
+
+// Values appends the interned value of every unique id to dst, in order.
+// If a unique id is not present, then the values resolved so far & an ErrUnknownID error are returned.
+func Values[T comparable](intern GenericIntern[T], uniqueIDs []uint64, dst []T) ([]T, error) {
+	if batch, ok := intern.(BatchIntern[T]); ok {
+		return batch.Values(uniqueIDs, dst)
+	}
+	return values(intern, uniqueIDs, dst)
+}
+
+// DeduplicateAll replaces every input with its interned value, in place.
+func DeduplicateAll[T comparable](intern GenericIntern[T], inputs []T) {
+	if batch, ok := intern.(BatchIntern[T]); ok {
+		batch.DeduplicateAll(inputs)
+		return
+	}
+	deduplicateAll(intern, inputs)
+}
+
+func insertAll[T comparable](intern GenericIntern[T], inputs []T, dst []uint64) []uint64 {
+	dst = slices.Grow(dst, len(inputs))
+	for _, input := range inputs {
+		dst = append(dst, intern.Insert(input))
+	}
+	return dst
+}
+
+func values[T comparable](intern GenericIntern[T], uniqueIDs []uint64, dst []T) ([]T, error) {
+	dst = slices.Grow(dst, len(uniqueIDs))
+	for _, uniqueID := range uniqueIDs {
+		output, ok := intern.Value(uniqueID)
+		if !ok {
+			return dst, fmt.Errorf("%w: %d", ErrUnknownID, uniqueID)
+		}
+		dst = append(dst, output)
+	}
+	return dst, nil
+}
+
+func deduplicateAll[T comparable](intern GenericIntern[T], inputs []T) {
+	for index, input := range inputs {
+		inputs[index] = intern.Deduplicate(input)
+	}
+}
+
+func (i *genericIntern[T]) InsertAll(inputs []T, dst []uint64) []uint64 {
+	return insertAll[T](i, inputs, dst)
+}
+
+func (i *genericIntern[T]) Values(uniqueIDs []uint64, dst []T) ([]T, error) {
+	return values[T](i, uniqueIDs, dst)
+}
+
+func (i *genericIntern[T]) DeduplicateAll(inputs []T) {
+	deduplicateAll[T](i, inputs)
+}
+
+func (i *safeGeneric[T]) InsertAll(inputs []T, dst []uint64) []uint64 {
+	i.RWMutex.Lock()
+	dst = insertAll(i.intern, inputs, dst)
+	i.RWMutex.Unlock()
+	return dst
+}
+
+func (i *safeGeneric[T]) Values(uniqueIDs []uint64, dst []T) (output []T, err error) {
+	i.RWMutex.RLock()
+	output, err = values(i.intern, uniqueIDs, dst)
+	i.RWMutex.RUnlock()
+	return output, err
+}
+
+func (i *safeGeneric[T]) DeduplicateAll(inputs []T) {
+	i.RWMutex.Lock()
+	deduplicateAll(i.intern, inputs)
+	i.RWMutex.Unlock()
+}
@@ -0,0 +1,47 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	for name, i := range map[string]GenericIntern[string]{
+		"generic": New[string](),
+		"safe":    NewSafe[string](),
+		"bounded": NewBounded[string](1_000, LRU, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			inputs := randomStringInputs(1_00)
+
+			ids := InsertAll(i, inputs, []uint64{0})
+			require.Len(t, ids, len(inputs)+1)
+			for index, input := range inputs {
+				require.EqualValues(t, i.Insert(input), ids[index+1])
+			}
+
+			outputs, err := Values(i, ids[1:], nil)
+			require.NoError(t, err)
+			require.EqualValues(t, inputs, outputs)
+
+			outputs, err = Values(i, []uint64{ids[1], 0}, nil)
+			require.ErrorIs(t, err, ErrUnknownID)
+			require.EqualValues(t, inputs[:1], outputs)
+
+			deduplicated := append([]string(nil), inputs...)
+			DeduplicateAll(i, deduplicated)
+			require.EqualValues(t, inputs, deduplicated)
+		})
+	}
+}
+
+func BenchmarkSafeInternAll(b *testing.B) {
+	inputs := randomStringInputs(10_000)
+
+	i := NewSafe[string]()
+
+	var ids []uint64
+	for index := 0; index < b.N; index += len(inputs) {
+		ids = InsertAll(i, inputs, ids[:0])
+	}
+}
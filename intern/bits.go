@@ -0,0 +1,21 @@
+package intern
+
+// mix is the splitmix64 finalizer of the hash xor the seed
+func mix(hash, seed uint64) uint64 {
+	hash ^= seed
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	return hash
+}
+
+// nextPowerOfTwo returns the smallest power of two >= value
+func nextPowerOfTwo(value uint64) uint64 {
+	output := uint64(1)
+	for output < value {
+		output <<= 1
+	}
+	return output
+}
@@ -0,0 +1,282 @@
+package intern
+
+import (
+	"container/heap"
+	"container/list"
+	"log"
+)
+
+// EvictionPolicy selects which entry a bounded intern table evicts once it is full
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently inserted or deduplicated entry
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently inserted or deduplicated entry, ties are broken by age
+	LFU
+	// Clock evicts with the CLOCK (second chance) approximation of LRU
+	Clock
+)
+
+var _ GenericIntern[int] = &boundedIntern[int]{}
+
+// boundedIntern implements the GenericIntern interface with a hard cap on the number of entries.
+// Unique ids are never reused, so Value(id) reports `ok=false` for evicted ids rather than returning another value.
+type boundedIntern[T comparable] struct {
+	keys       map[T]uint64
+	values     map[uint64]T
+	counter    uint64
+	maxEntries int
+	evictor    evictor
+	onEvict    func(uniqueID uint64, value T)
+}
+
+// NewBounded creates a new GenericIntern[T] instance holding at most maxEntries values.
+// When a new value is inserted into a full table, then the victim chosen by the policy is removed & reported to onEvict (if not nil).
+// Only Insert & Deduplicate count as a use of an entry, Value(id) does not change the eviction order.
+func NewBounded[T comparable](maxEntries int, policy EvictionPolicy, onEvict func(uniqueID uint64, value T)) GenericIntern[T] {
+	if maxEntries <= 0 {
+		log.Panicf("maxEntries(%d) must be > 0", maxEntries)
+	}
+
+	output := &boundedIntern[T]{
+		keys:       map[T]uint64{},
+		values:     map[uint64]T{},
+		maxEntries: maxEntries,
+		onEvict:    onEvict,
+	}
+	switch policy {
+	case LRU:
+		output.evictor = newLRUEvictor()
+	case LFU:
+		output.evictor = newLFUEvictor()
+	case Clock:
+		output.evictor = newClockEvictor()
+	default:
+		log.Panicf("unknown eviction policy %d", policy)
+	}
+	return output
+}
+
+func (i *boundedIntern[T]) Deduplicate(input T) T {
+	uniqueId := i.Insert(input)
+	return i.values[uniqueId]
+}
+
+func (i *boundedIntern[T]) Insert(input T) uint64 {
+	if uniqueId, ok := i.keys[input]; ok {
+		i.evictor.touch(uniqueId)
+		return uniqueId
+	}
+
+	if len(i.values) >= i.maxEntries {
+		victim := i.evictor.evict()
+		value := i.values[victim]
+		delete(i.keys, value)
+		delete(i.values, victim)
+		if nil != i.onEvict {
+			i.onEvict(victim, value)
+		}
+	}
+
+	i.counter++
+	uniqueId := i.counter
+	i.keys[input] = uniqueId
+	i.values[uniqueId] = input
+	i.evictor.add(uniqueId)
+	return uniqueId
+}
+
+func (i *boundedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *boundedIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *boundedIntern[T]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.evictor.clear()
+	i.counter = 0
+}
+
+// evictor tracks the usage of unique ids & picks the next one to evict
+type evictor interface {
+	// add starts tracking a new unique id
+	add(uniqueID uint64)
+	// touch records a use of an existing unique id
+	touch(uniqueID uint64)
+	// evict stops tracking & returns the next victim, it is only called when at least one id is tracked
+	evict() (uniqueID uint64)
+	// clear stops tracking every unique id
+	clear()
+}
+
+// lruEvictor keeps unique ids in recency order, the front of the list is the most recently used
+type lruEvictor struct {
+	order    *list.List
+	elements map[uint64]*list.Element
+}
+
+func newLRUEvictor() *lruEvictor {
+	return &lruEvictor{order: list.New(), elements: map[uint64]*list.Element{}}
+}
+
+func (e *lruEvictor) add(uniqueID uint64) {
+	e.elements[uniqueID] = e.order.PushFront(uniqueID)
+}
+
+func (e *lruEvictor) touch(uniqueID uint64) {
+	e.order.MoveToFront(e.elements[uniqueID])
+}
+
+func (e *lruEvictor) evict() uint64 {
+	uniqueID := e.order.Remove(e.order.Back()).(uint64)
+	delete(e.elements, uniqueID)
+	return uniqueID
+}
+
+func (e *lruEvictor) clear() {
+	e.order.Init()
+	clear(e.elements)
+}
+
+// lfuEvictor keeps unique ids in a min-heap ordered by use count, then by insertion order
+type lfuEvictor struct {
+	entries lfuHeap
+	lookup  map[uint64]*lfuEntry
+	seq     uint64
+}
+
+type lfuEntry struct {
+	uniqueID uint64
+	uses     uint64
+	seq      uint64
+	index    int
+}
+
+func newLFUEvictor() *lfuEvictor {
+	return &lfuEvictor{lookup: map[uint64]*lfuEntry{}}
+}
+
+func (e *lfuEvictor) add(uniqueID uint64) {
+	e.seq++
+	entry := &lfuEntry{uniqueID: uniqueID, uses: 1, seq: e.seq}
+	e.lookup[uniqueID] = entry
+	heap.Push(&e.entries, entry)
+}
+
+func (e *lfuEvictor) touch(uniqueID uint64) {
+	entry := e.lookup[uniqueID]
+	entry.uses++
+	heap.Fix(&e.entries, entry.index)
+}
+
+func (e *lfuEvictor) evict() uint64 {
+	entry := heap.Pop(&e.entries).(*lfuEntry)
+	delete(e.lookup, entry.uniqueID)
+	return entry.uniqueID
+}
+
+func (e *lfuEvictor) clear() {
+	clear(e.entries)
+	e.entries = e.entries[:0]
+	clear(e.lookup)
+	e.seq = 0
+}
+
+// lfuHeap implements heap.Interface for lfuEntry
+type lfuHeap []*lfuEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(a, b int) bool {
+	if h[a].uses != h[b].uses {
+		return h[a].uses < h[b].uses
+	}
+	return h[a].seq < h[b].seq
+}
+
+func (h lfuHeap) Swap(a, b int) {
+	h[a], h[b] = h[b], h[a]
+	h[a].index = a
+	h[b].index = b
+}
+
+func (h *lfuHeap) Push(x any) {
+	entry := x.(*lfuEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// clockEvictor keeps unique ids in a ring with a reference bit, the hand clears bits until it finds an unreferenced slot
+type clockEvictor struct {
+	slots []clockSlot
+	index map[uint64]int
+	hand  int
+	// free is the slot emptied by the last eviction, or -1
+	free int
+}
+
+type clockSlot struct {
+	uniqueID   uint64
+	referenced bool
+}
+
+func newClockEvictor() *clockEvictor {
+	return &clockEvictor{index: map[uint64]int{}, free: -1}
+}
+
+func (e *clockEvictor) add(uniqueID uint64) {
+	slot := clockSlot{uniqueID: uniqueID}
+	if e.free >= 0 {
+		e.slots[e.free] = slot
+		e.index[uniqueID] = e.free
+		e.free = -1
+		return
+	}
+	e.index[uniqueID] = len(e.slots)
+	e.slots = append(e.slots, slot)
+}
+
+func (e *clockEvictor) touch(uniqueID uint64) {
+	e.slots[e.index[uniqueID]].referenced = true
+}
+
+func (e *clockEvictor) evict() uint64 {
+	for {
+		if e.hand >= len(e.slots) {
+			e.hand = 0
+		}
+		slot := &e.slots[e.hand]
+		if slot.referenced {
+			slot.referenced = false
+			e.hand++
+			continue
+		}
+
+		uniqueID := slot.uniqueID
+		delete(e.index, uniqueID)
+		e.free = e.hand
+		e.hand++
+		return uniqueID
+	}
+}
+
+func (e *clockEvictor) clear() {
+	e.slots = e.slots[:0]
+	clear(e.index)
+	e.hand = 0
+	e.free = -1
+}
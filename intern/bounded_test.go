@@ -0,0 +1,63 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBoundedIntern(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy EvictionPolicy
+		victim string
+	}{
+		{name: "lru", policy: LRU, victim: "b"},
+		{name: "lfu", policy: LFU, victim: "a"},
+		{name: "clock", policy: Clock, victim: "a"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evicted := map[uint64]string{}
+			i := NewBounded[string](3, tc.policy, func(uniqueID uint64, value string) {
+				evicted[uniqueID] = value
+			})
+
+			a := i.Insert("a")
+			b := i.Insert("b")
+			c := i.Insert("c")
+			require.EqualValues(t, 3, i.Len())
+
+			// "b" is the most frequently used, "c" the most recently used & "a" is older than "c"
+			require.EqualValues(t, b, i.Insert("b"))
+			require.EqualValues(t, "b", i.Deduplicate("b"))
+			require.EqualValues(t, a, i.Insert("a"))
+			require.EqualValues(t, "c", i.Deduplicate("c"))
+
+			d := i.Insert("d")
+			require.EqualValues(t, 3, i.Len())
+			require.Len(t, evicted, 1)
+
+			ids := map[string]uint64{"a": a, "b": b, "c": c}
+			victimID := ids[tc.victim]
+			require.EqualValues(t, tc.victim, evicted[victimID])
+
+			output, ok := i.Value(victimID)
+			require.False(t, ok)
+			require.Zero(t, output)
+
+			output, ok = i.Value(d)
+			require.True(t, ok)
+			require.EqualValues(t, "d", output)
+
+			require.NotEqualValues(t, victimID, i.Insert(tc.victim))
+			require.EqualValues(t, 3, i.Len())
+			require.Len(t, evicted, 2)
+
+			i.Clear()
+			require.Zero(t, i.Len())
+			for _, input := range randomStringInputs(1_00) {
+				require.EqualValues(t, input, i.Deduplicate(input))
+				require.LessOrEqual(t, i.Len(), 3)
+			}
+		})
+	}
+}
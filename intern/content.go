@@ -0,0 +1,181 @@
+package intern
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrUnequalValue is returned by content-addressed intern tables for values that are not equal to themselves, e.g. NaN
+var ErrUnequalValue = errors.New("intern: value is not equal to itself")
+
+// CollisionPolicy selects how a content-addressed intern table handles two distinct values with the same hash
+type CollisionPolicy int
+
+const (
+	// ReportCollisions rejects the second value: TryInsert returns an ErrHashCollision error & Insert panics
+	ReportCollisions CollisionPolicy = iota
+	// ProbeCollisions assigns the second value the next free id of its probe sequence.
+	// Probed ids depend on insertion order, so processes only agree on them if they insert colliding values in the same order.
+	ProbeCollisions
+)
+
+// contentMaxProbes is the number of probes tried before giving up on a value
+const contentMaxProbes = 64
+
+// ContentAddressedIntern is a GenericIntern whose unique ids are a stable, seeded 63-bit hash of the encoded value,
+// so independent processes using the same seed & codec produce identical ids for identical values without coordination.
+//
+// Values that are == must encode the same way: floating point zeros are hashed as +0, & a custom codec must do likewise.
+// Values that are not equal to themselves, e.g. NaN, cannot be found again so they are rejected with ErrUnequalValue.
+type ContentAddressedIntern[T comparable] interface {
+	GenericIntern[T]
+	Lookup[T]
+
+	// TryInsert is Insert, returning an ErrHashCollision or ErrUnequalValue error instead of panicking
+	TryInsert(input T) (uint64, error)
+
+	// HashID returns the unique id the input hashes to, without inserting it.
+	// It is the id Insert returns, unless the input collides with another value.
+	HashID(input T) (uint64, error)
+}
+
+var _ ContentAddressedIntern[int] = &contentIntern[int]{}
+
+// contentIntern implements the ContentAddressedIntern interface
+type contentIntern[T comparable] struct {
+	keys   map[T]uint64
+	values map[uint64]T
+	seed   uint64
+	codec  Codec[T]
+	policy CollisionPolicy
+	buf    []byte
+}
+
+// NewContentAddressed creates a new ContentAddressedIntern[T] instance hashing values encoded by the codec with the seed.
+// If codec is nil, then DefaultCodec[T]() is used, & NewContentAddressed panics if T has no default codec.
+func NewContentAddressed[T comparable](seed uint64, codec Codec[T], policy CollisionPolicy) ContentAddressedIntern[T] {
+	if nil == codec {
+		var err error
+		if codec, err = DefaultCodec[T](); err != nil {
+			log.Panic(err)
+		}
+	}
+	if policy != ReportCollisions && policy != ProbeCollisions {
+		log.Panicf("unknown collision policy %d", policy)
+	}
+	return &contentIntern[T]{
+		keys:   map[T]uint64{},
+		values: map[uint64]T{},
+		seed:   seed,
+		codec:  codec,
+		policy: policy,
+	}
+}
+
+func (i *contentIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)]
+}
+
+func (i *contentIntern[T]) Insert(input T) uint64 {
+	uniqueID, err := i.TryInsert(input)
+	if err != nil {
+		log.Panic(err)
+	}
+	return uniqueID
+}
+
+func (i *contentIntern[T]) TryInsert(input T) (uint64, error) {
+	if uniqueID, ok := i.keys[input]; ok {
+		return uniqueID, nil
+	}
+
+	uniqueID, err := i.HashID(input)
+	if err != nil {
+		return 0, err
+	}
+	for probe := uint64(1); ; probe++ {
+		other, ok := i.values[uniqueID]
+		if !ok {
+			break
+		}
+		if i.policy == ReportCollisions {
+			return 0, fmt.Errorf("%w: %v & %v both hash to %d", ErrHashCollision, other, input, uniqueID)
+		}
+		if probe > contentMaxProbes {
+			return 0, fmt.Errorf("%w: no free id for %v after %d probes", ErrHashCollision, input, contentMaxProbes)
+		}
+		uniqueID = contentID(mix(uniqueID, probe))
+	}
+
+	i.keys[input] = uniqueID
+	i.values[uniqueID] = input
+	return uniqueID, nil
+}
+
+func (i *contentIntern[T]) HashID(input T) (uint64, error) {
+	if input != input {
+		return 0, fmt.Errorf("%w: %v", ErrUnequalValue, input)
+	}
+
+	var err error
+	if i.buf, err = i.codec.AppendBinary(i.buf[:0], canonicalZero(input)); err != nil {
+		return 0, err
+	}
+	return contentID(StableHash(i.seed, i.buf)), nil
+}
+
+func (i *contentIntern[T]) ID(input T) (uint64, bool) {
+	uniqueID, ok := i.keys[input]
+	return uniqueID, ok
+}
+
+func (i *contentIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *contentIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *contentIntern[T]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+}
+
+// StableHash is a seeded 64-bit hash of data (FNV-1a with a seeded offset basis, & a splitmix64 finalizer).
+// Unlike hash/maphash, it is stable across processes & releases of this package, so it can be used for ids shared between processes.
+func StableHash(seed uint64, data []byte) uint64 {
+	hash := uint64(14695981039346656037) ^ mix(seed, 0)
+	for _, b := range data {
+		hash ^= uint64(b)
+		hash *= 1099511628211
+	}
+	return mix(hash, uint64(len(data)))
+}
+
+// canonicalZero returns +0 for floating point zeros, so -0 & +0, which are ==, hash the same
+func canonicalZero[T comparable](input T) T {
+	switch value := any(input).(type) {
+	case float32:
+		if value == 0 {
+			return any(float32(0)).(T)
+		}
+	case float64:
+		if value == 0 {
+			return any(float64(0)).(T)
+		}
+	}
+	return input
+}
+
+// contentID maps a hash to a unique id.
+// 0 is not a valid unique id, & the overlayBit is reserved so content-addressed tables can be the parent of NewOverlay.
+func contentID(hash uint64) uint64 {
+	uniqueID := hash &^ overlayBit
+	if uniqueID == 0 {
+		return 1
+	}
+	return uniqueID
+}
@@ -0,0 +1,102 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestContentAddressedIntern(t *testing.T) {
+	i := NewContentAddressed[string](42, nil, ReportCollisions)
+	other := NewContentAddressed[string](42, nil, ReportCollisions)
+	reseeded := NewContentAddressed[string](43, nil, ReportCollisions)
+
+	uniqueID := i.Insert("value")
+	require.NotZero(t, uniqueID)
+	require.EqualValues(t, uniqueID, i.Insert("value"))
+	require.EqualValues(t, "value", i.Deduplicate("value"))
+
+	// Independent tables agree on ids, whatever the insertion order
+	other.Insert("another value")
+	require.EqualValues(t, uniqueID, other.Insert("value"))
+	require.NotEqualValues(t, uniqueID, reseeded.Insert("value"))
+
+	// The ids are stable across releases, changing them breaks every stored id
+	require.EqualValues(t, StableHash(42, []byte("value"))&^overlayBit, uniqueID)
+	require.EqualValues(t, uint64(0x6ae2bd775feb0083), StableHash(0, []byte("value")))
+
+	hashID, err := i.HashID("unseen")
+	require.NoError(t, err)
+	_, ok := i.ID("unseen")
+	require.False(t, ok)
+	require.EqualValues(t, hashID, i.Insert("unseen"))
+
+	output, ok := i.Value(uniqueID)
+	require.True(t, ok)
+	require.EqualValues(t, "value", output)
+	require.EqualValues(t, 2, i.Len())
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	require.EqualValues(t, uniqueID, i.Insert("value"))
+
+	ints := NewContentAddressed[int](42, nil, ReportCollisions)
+	require.EqualValues(t, ints.Insert(123), NewContentAddressed[int](42, nil, ReportCollisions).Insert(123))
+}
+
+// collidingCodec encodes every value the same way, to force collisions
+type collidingCodec struct{}
+
+func (collidingCodec) AppendBinary(dst []byte, _ string) ([]byte, error) { return dst, nil }
+func (collidingCodec) DecodeBinary([]byte) (string, error)               { return "", nil }
+
+func TestContentAddressedIntern_collisions(t *testing.T) {
+	report := NewContentAddressed[string](0, collidingCodec{}, ReportCollisions)
+	uniqueID := report.Insert("a")
+	_, err := report.TryInsert("b")
+	require.ErrorIs(t, err, ErrHashCollision)
+	require.Panics(t, func() { report.Insert("b") })
+	require.EqualValues(t, uniqueID, report.Insert("a"))
+
+	probe := NewContentAddressed[string](0, collidingCodec{}, ProbeCollisions)
+	require.EqualValues(t, uniqueID, probe.Insert("a"))
+	probed := probe.Insert("b")
+	require.NotEqualValues(t, uniqueID, probed)
+	require.EqualValues(t, probed, probe.Insert("b"))
+
+	output, ok := probe.Value(probed)
+	require.True(t, ok)
+	require.EqualValues(t, "b", output)
+}
+
+func TestContentAddressedIntern_overlay(t *testing.T) {
+	parent := NewContentAddressed[string](42, nil, ReportCollisions)
+	inputs := make([]string, 100)
+	for index := range inputs {
+		inputs[index] = strconv.Itoa(index)
+		require.Zero(t, parent.Insert(inputs[index])&overlayBit)
+	}
+
+	i := NewOverlay[string](parent)
+	for _, input := range append(inputs, "local") {
+		output, ok := i.Value(i.Insert(input))
+		require.True(t, ok)
+		require.EqualValues(t, input, output)
+	}
+	require.EqualValues(t, 101, i.Len())
+}
+
+func TestContentAddressedIntern_floats(t *testing.T) {
+	positive := NewContentAddressed[float64](42, nil, ReportCollisions)
+	negative := NewContentAddressed[float64](42, nil, ReportCollisions)
+	zero := positive.Insert(0)
+	require.EqualValues(t, zero, positive.Insert(math.Copysign(0, -1)))
+	require.EqualValues(t, zero, negative.Insert(math.Copysign(0, -1)))
+	require.EqualValues(t, zero, negative.Insert(0))
+
+	_, err := positive.TryInsert(math.NaN())
+	require.ErrorIs(t, err, ErrUnequalValue)
+	require.Panics(t, func() { positive.Insert(math.NaN()) })
+	require.EqualValues(t, 1, positive.Len())
+}
@@ -0,0 +1,82 @@
+package intern
+
+import (
+	"log"
+)
+
+// ID is the constraint for the width of unique ids handed out by a DenseIntern
+type ID interface {
+	~uint16 | ~uint32 | ~uint64
+}
+
+// DenseIntern is the GenericIntern interface with a configurable unique id width.
+// A DenseIntern[T, uint64] also implements GenericIntern[T].
+type DenseIntern[T comparable, I ID] interface {
+	// Deduplicate deduplicates the value by inserting it to the map & reading it back out.
+	Deduplicate(input T) (output T)
+
+	// Insert attempts to insert key to the map & returns its unique id.
+	// It panics if the table already holds the maximum number of values representable by I.
+	Insert(input T) (uniqueID I)
+
+	// Value returns the interned value for the given unique id
+	Value(uniqueID I) (output T, ok bool)
+
+	// Len returns the size of the map (number of keys)
+	Len() int
+
+	// Clear deletes the interned values & reset the counter back to 0.
+	Clear()
+}
+
+var _ DenseIntern[int, uint16] = &denseIntern[int, uint16]{}
+var _ DenseIntern[int, uint32] = &denseIntern[int, uint32]{}
+var _ GenericIntern[int] = &denseIntern[int, uint64]{}
+
+// denseIntern implements the DenseIntern interface, storing values in a slice indexed by `uniqueID-1`
+type denseIntern[T comparable, I ID] struct {
+	keys   map[T]I
+	values []T
+}
+
+// NewDense creates a new DenseIntern[T, I] instance, e.g. NewDense[string, uint32]() for compact 32-bit ids
+func NewDense[T comparable, I ID]() DenseIntern[T, I] {
+	return &denseIntern[T, I]{
+		keys: map[T]I{},
+	}
+}
+
+func (i *denseIntern[T, I]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)-1]
+}
+
+func (i *denseIntern[T, I]) Insert(input T) I {
+	if uniqueId, ok := i.keys[input]; ok {
+		return uniqueId
+	}
+
+	uniqueId := I(len(i.values) + 1)
+	if int(uniqueId) != len(i.values)+1 {
+		log.Panicf("intern is full, %d values do not fit in %T ids", len(i.values)+1, uniqueId)
+	}
+	i.keys[input] = uniqueId
+	i.values = append(i.values, input)
+	return uniqueId
+}
+
+func (i *denseIntern[T, I]) Value(uniqueID I) (output T, ok bool) {
+	if uniqueID == 0 || uint64(uniqueID) > uint64(len(i.values)) {
+		return output, false
+	}
+	return i.values[uniqueID-1], true
+}
+
+func (i *denseIntern[T, I]) Len() int {
+	return len(i.values)
+}
+
+func (i *denseIntern[T, I]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.values = i.values[:0]
+}
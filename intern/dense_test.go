@@ -0,0 +1,53 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestDenseIntern(t *testing.T) {
+	i := NewDense[string, uint32]()
+	require.Zero(t, i.Len())
+
+	output, ok := i.Value(0)
+	require.False(t, ok)
+	require.Zero(t, output)
+
+	index := i.Insert("value")
+	require.EqualValues(t, 1, index)
+
+	output, ok = i.Value(index)
+	require.True(t, ok)
+	require.EqualValues(t, output, "value")
+	require.EqualValues(t, index, i.Insert("value"))
+
+	_, ok = i.Value(index + 1)
+	require.False(t, ok)
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	_, ok = i.Value(index)
+	require.False(t, ok)
+
+	inputs := randomStringInputs(1_00)
+	unique := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		unique[input] = input
+		require.EqualValues(t, input, i.Deduplicate(input))
+	}
+	require.EqualValues(t, len(unique), i.Len())
+
+	var generic GenericIntern[string] = NewDense[string, uint64]()
+	require.EqualValues(t, 1, generic.Insert("value"))
+}
+
+func TestDenseIntern_full(t *testing.T) {
+	i := NewDense[int, uint16]()
+	for input := 0; input < math.MaxUint16; input++ {
+		i.Insert(input)
+	}
+	require.EqualValues(t, math.MaxUint16, i.Len())
+	require.EqualValues(t, math.MaxUint16, i.Insert(math.MaxUint16-1))
+	require.Panics(t, func() { i.Insert(math.MaxUint16) })
+}
@@ -0,0 +1,120 @@
+package intern
+
+import (
+	"log"
+	"math"
+)
+
+// DictVector is a dictionary-encoded column of values: every value is interned into the dictionary,
+// & the vector only stores the compact 32-bit unique id (code) of each row.
+//
+// Slices of a DictVector share its dictionary, use Reencode to build a smaller dictionary with only the values in use.
+type DictVector[T comparable] struct {
+	dictionary GenericIntern[T]
+	codes      []uint32
+}
+
+// NewDictVector creates a new DictVector[T] using the dictionary, if dictionary is nil, then New[T]() is used.
+func NewDictVector[T comparable](dictionary GenericIntern[T]) *DictVector[T] {
+	if nil == dictionary {
+		dictionary = New[T]()
+	}
+	return &DictVector[T]{dictionary: dictionary}
+}
+
+// Append interns the values & appends their codes to the vector.
+// It panics if a unique id does not fit in 32 bits.
+func (v *DictVector[T]) Append(values ...T) {
+	for _, value := range values {
+		uniqueID := v.dictionary.Insert(value)
+		if uniqueID > math.MaxUint32 {
+			log.Panicf("unique id %d does not fit in a uint32 code", uniqueID)
+		}
+		v.codes = append(v.codes, uint32(uniqueID))
+	}
+}
+
+// At decodes the value of the i-th row
+func (v *DictVector[T]) At(i int) T {
+	output, _ := v.dictionary.Value(uint64(v.codes[i]))
+	return output
+}
+
+// Len returns the number of rows
+func (v *DictVector[T]) Len() int {
+	return len(v.codes)
+}
+
+// Codes returns the code of every row, the codes are the unique ids of the values in the dictionary.
+// The slice is shared with the vector & must not be modified.
+func (v *DictVector[T]) Codes() []uint32 {
+	return v.codes
+}
+
+// Dictionary returns the intern table the values are encoded with
+func (v *DictVector[T]) Dictionary() GenericIntern[T] {
+	return v.dictionary
+}
+
+// Filter appends the index of every row equal to value to dst, comparing codes instead of decoding the rows.
+func (v *DictVector[T]) Filter(value T, dst []int) []int {
+	lookup, ok := v.dictionary.(Lookup[T])
+	if !ok {
+		return v.FilterFunc(func(input T) bool { return input == value }, dst)
+	}
+
+	uniqueID, ok := lookup.ID(value)
+	if !ok || uniqueID > math.MaxUint32 {
+		return dst
+	}
+
+	code := uint32(uniqueID)
+	for index, c := range v.codes {
+		if c == code {
+			dst = append(dst, index)
+		}
+	}
+	return dst
+}
+
+// FilterFunc appends the index of every row matching the predicate to dst.
+// The predicate is evaluated once per distinct code, not once per row.
+func (v *DictVector[T]) FilterFunc(predicate func(T) bool, dst []int) []int {
+	matches := map[uint32]bool{}
+	for index, code := range v.codes {
+		match, ok := matches[code]
+		if !ok {
+			value, _ := v.dictionary.Value(uint64(code))
+			match = predicate(value)
+			matches[code] = match
+		}
+		if match {
+			dst = append(dst, index)
+		}
+	}
+	return dst
+}
+
+// Slice returns the rows [start, end) as a new vector sharing the dictionary & the codes
+func (v *DictVector[T]) Slice(start, end int) *DictVector[T] {
+	return &DictVector[T]{dictionary: v.dictionary, codes: v.codes[start:end:end]}
+}
+
+// Reencode returns a copy of the vector with a new dictionary (from New[T]) holding only the values in use,
+// with codes assigned in order of first appearance.
+func (v *DictVector[T]) Reencode() *DictVector[T] {
+	output := NewDictVector[T](nil)
+	output.codes = make([]uint32, len(v.codes))
+
+	remap := map[uint32]uint32{}
+	for index, code := range v.codes {
+		newCode, ok := remap[code]
+		if !ok {
+			value, _ := v.dictionary.Value(uint64(code))
+			newCode = uint32(output.dictionary.Insert(value))
+			remap[code] = newCode
+		}
+		output.codes[index] = newCode
+	}
+	return output
+}
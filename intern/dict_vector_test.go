@@ -0,0 +1,45 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDictVector(t *testing.T) {
+	v := NewDictVector[string](nil)
+	require.Zero(t, v.Len())
+
+	v.Append("a", "b", "a", "c", "b", "a")
+	require.EqualValues(t, 6, v.Len())
+	require.EqualValues(t, []uint32{1, 2, 1, 3, 2, 1}, v.Codes())
+	require.EqualValues(t, 3, v.Dictionary().Len())
+	for index, value := range []string{"a", "b", "a", "c", "b", "a"} {
+		require.EqualValues(t, value, v.At(index))
+	}
+
+	require.EqualValues(t, []int{0, 2, 5}, v.Filter("a", nil))
+	require.Empty(t, v.Filter("missing", nil))
+	require.EqualValues(t, []int{1, 3, 4}, v.FilterFunc(func(value string) bool {
+		return value > "a"
+	}, nil))
+
+	sliced := v.Slice(3, 5)
+	require.EqualValues(t, 2, sliced.Len())
+	require.EqualValues(t, "c", sliced.At(0))
+	require.Same(t, v.Dictionary(), sliced.Dictionary())
+
+	sliced.Append("d")
+	require.EqualValues(t, "a", v.At(5))
+
+	reencoded := sliced.Reencode()
+	require.EqualValues(t, []uint32{1, 2, 3}, reencoded.Codes())
+	require.EqualValues(t, 3, reencoded.Dictionary().Len())
+	for index := 0; index < sliced.Len(); index++ {
+		require.EqualValues(t, sliced.At(index), reencoded.At(index))
+	}
+
+	bounded := NewDictVector[string](NewBounded[string](10, LRU, nil))
+	bounded.Append(strings.Split("x y x", " ")...)
+	require.EqualValues(t, []int{0, 2}, bounded.Filter("x", nil))
+}
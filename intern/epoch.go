@@ -0,0 +1,129 @@
+package intern
+
+import (
+	"log"
+	"math"
+)
+
+// EpochIntern is a GenericIntern whose entries expire by generation instead of all at once with Clear.
+// Insert & Deduplicate mark their value as used in the current epoch without changing its unique id, Value does not count as a use.
+// Advance drops the entries unused for `retain` epochs.
+// Unique ids encode the epoch they were issued in, so Value returns `ok=false` for an expired id rather than another value.
+type EpochIntern[T comparable] interface {
+	GenericIntern[T]
+
+	// Advance starts a new epoch & drops the entries that have not been used in the last `retain` epochs.
+	Advance()
+
+	// Epoch returns the current epoch
+	Epoch() uint64
+}
+
+var _ EpochIntern[int] = &epochIntern[int]{}
+
+const (
+	// epochSeqBits is the number of low bits of a unique id that hold its sequence within the epoch it was issued in
+	epochSeqBits = 32
+	epochSeqMask = 1<<epochSeqBits - 1
+)
+
+// epochIntern implements the EpochIntern interface with a ring of the ids used in each of the last `retain+1` epochs
+type epochIntern[T comparable] struct {
+	keys   map[T]uint64
+	values map[uint64]epochEntry[T]
+	// used holds the unique ids first used in each retained epoch, indexed by `epoch % (retain+1)`.
+	// An id is listed once per epoch it is used in, & only dropped if it was not used since.
+	used  [][]uint64
+	epoch uint64
+	seq   uint64
+}
+
+// epochEntry is an interned value & the last epoch it was used in
+type epochEntry[T comparable] struct {
+	value T
+	epoch uint64
+}
+
+// NewEpoch creates a new EpochIntern[T] instance where unique ids stay valid for retain epochs after the one they were last used in.
+func NewEpoch[T comparable](retain int) EpochIntern[T] {
+	if retain < 0 {
+		log.Panicf("retain(%d) must be >= 0", retain)
+	}
+	return &epochIntern[T]{
+		keys:   map[T]uint64{},
+		values: map[uint64]epochEntry[T]{},
+		used:   make([][]uint64, retain+1),
+	}
+}
+
+func (i *epochIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)].value
+}
+
+func (i *epochIntern[T]) Insert(input T) uint64 {
+	if uniqueID, ok := i.keys[input]; ok {
+		if entry := i.values[uniqueID]; entry.epoch != i.epoch {
+			entry.epoch = i.epoch
+			i.values[uniqueID] = entry
+			i.use(uniqueID)
+		}
+		return uniqueID
+	}
+
+	if i.seq >= epochSeqMask {
+		log.Panicf("epoch %d is full, %d values do not fit in %d bits", i.epoch, i.seq+1, epochSeqBits)
+	}
+	i.seq++
+	uniqueID := i.epoch<<epochSeqBits | i.seq
+	i.keys[input] = uniqueID
+	i.values[uniqueID] = epochEntry[T]{value: input, epoch: i.epoch}
+	i.use(uniqueID)
+	return uniqueID
+}
+
+func (i *epochIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	entry, ok := i.values[uniqueID]
+	return entry.value, ok
+}
+
+func (i *epochIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *epochIntern[T]) Advance() {
+	if i.epoch == math.MaxUint32 {
+		log.Panic("epochs are exhausted")
+	}
+	i.epoch++
+	i.seq = 0
+
+	// The slot of the new epoch holds the ids used in the oldest one, which expire now unless used since
+	used := &i.used[i.epoch%uint64(len(i.used))]
+	for _, uniqueID := range *used {
+		if entry, ok := i.values[uniqueID]; ok && i.epoch-entry.epoch >= uint64(len(i.used)) {
+			delete(i.keys, entry.value)
+			delete(i.values, uniqueID)
+		}
+	}
+	*used = (*used)[:0]
+}
+
+func (i *epochIntern[T]) Epoch() uint64 {
+	return i.epoch
+}
+
+// Clear deletes every value & starts a new epoch, all previously issued unique ids become invalid.
+func (i *epochIntern[T]) Clear() {
+	for index := range i.used {
+		i.used[index] = i.used[index][:0]
+	}
+	clear(i.keys)
+	clear(i.values)
+	i.Advance()
+}
+
+// use records that the unique id was used in the current epoch
+func (i *epochIntern[T]) use(uniqueID uint64) {
+	slot := i.epoch % uint64(len(i.used))
+	i.used[slot] = append(i.used[slot], uniqueID)
+}
@@ -0,0 +1,74 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEpochIntern(t *testing.T) {
+	i := NewEpoch[string](1)
+	require.Zero(t, i.Epoch())
+
+	_, ok := i.Value(0)
+	require.False(t, ok)
+
+	hot := i.Insert("hot")
+	cold := i.Insert("cold")
+	require.EqualValues(t, hot, i.Insert("hot"))
+	require.EqualValues(t, 2, i.Len())
+
+	i.Advance()
+	require.EqualValues(t, 1, i.Epoch())
+
+	// Ids from the previous epoch are still valid, & using "hot" keeps its id
+	output, ok := i.Value(cold)
+	require.True(t, ok)
+	require.EqualValues(t, "cold", output)
+
+	require.EqualValues(t, hot, i.Insert("hot"))
+	require.EqualValues(t, "hot", i.Deduplicate("hot"))
+	require.EqualValues(t, 2, i.Len())
+
+	i.Advance()
+	require.EqualValues(t, 1, i.Len())
+	_, ok = i.Value(cold)
+	require.False(t, ok)
+	output, ok = i.Value(hot)
+	require.True(t, ok)
+	require.EqualValues(t, "hot", output)
+
+	// "cold" gets a new id in the current epoch, the expired one does not alias it
+	cold2 := i.Insert("cold")
+	require.NotEqualValues(t, cold, cold2)
+	_, ok = i.Value(cold)
+	require.False(t, ok)
+
+	// "hot" lives as long as it is used every epoch, reading it with Value does not count as a use
+	require.EqualValues(t, hot, i.Insert("hot"))
+	for range 3 {
+		i.Advance()
+		require.EqualValues(t, hot, i.Insert("hot"))
+	}
+	_, ok = i.Value(cold2)
+	require.False(t, ok)
+	require.EqualValues(t, 1, i.Len())
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	_, ok = i.Value(hot)
+	require.False(t, ok)
+	require.NotEqualValues(t, hot, i.Insert("hot"))
+	require.NotEqualValues(t, cold2, i.Insert("cold"))
+
+	_, ok = i.Value((i.Epoch()+1)<<epochSeqBits | 1)
+	require.False(t, ok)
+}
+
+func TestEpochIntern_noRetain(t *testing.T) {
+	i := NewEpoch[int](0)
+	uniqueID := i.Insert(1)
+	i.Advance()
+	_, ok := i.Value(uniqueID)
+	require.False(t, ok)
+	require.Zero(t, i.Len())
+}
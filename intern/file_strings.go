@@ -0,0 +1,434 @@
+//go:build linux || darwin
+
+package intern
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// File layout of a FileStrings dictionary at `path`:
+//
+//	path       data, segment 0 starts with the header, then records of `uint32 length | bytes` that never span segments
+//	path.off   the offset of every record in the data file, as uint64 indexed by `uniqueID-1`
+//	path.idx   open addressing hash index: magic | capacity uint64 | capacity * (uniqueID uint64, 0 is empty)
+//
+// All integers are little endian. The entry count in the header is only updated by Sync & Close,
+// so entries appended after the last Sync are discarded when the dictionary is reopened.
+const (
+	fileStringsMagic       = "GSTRDICT"
+	fileStringsIndexMagic  = "GSTRIDX1"
+	fileStringsVersion     = 1
+	fileStringsHeaderSize  = 64
+	fileStringsIndexHeader = 16
+	fileStringsRecordSize  = 4
+	fileStringsMinCapacity = 16
+
+	// DefaultSegmentSize is the size of the data file segments mapped into memory, & the limit on the length of a value
+	DefaultSegmentSize = 64 << 20
+)
+
+var (
+	// ErrFileStringsFormat is returned when opening a file that is not a FileStrings dictionary
+	ErrFileStringsFormat = errors.New("intern: not a string dictionary file")
+	// ErrValueTooLarge is returned when a value does not fit in a segment
+	ErrValueTooLarge = errors.New("intern: value too large")
+)
+
+var _ GenericIntern[string] = &FileStrings{}
+var _ Lookup[string] = &FileStrings{}
+
+// FileStrings is a persistent GenericIntern[string] backed by files on disk.
+// Values are appended to a data file that is memory mapped, so Value(id) returns zero-copy strings,
+// & reopening the same path restores the same unique ids.
+//
+// Strings returned by Deduplicate & Value point into the mapped file: they are only valid until Clear or Close.
+// Insert & Deduplicate panic on I/O errors, use TryInsert to handle them.
+//
+// FileStrings is not safe for concurrent use.
+type FileStrings struct {
+	data, offsetsFile, indexFile *os.File
+
+	segmentSize uint64
+	segments    [][]byte
+	dataEnd     uint64
+
+	offsets []uint64
+	index   []uint64
+	scratch []byte
+}
+
+// OpenFileStrings opens the dictionary at path, creating it if it does not exist.
+// The segmentSize only applies to new dictionaries, if segmentSize <= 0, then DefaultSegmentSize is used.
+func OpenFileStrings(path string, segmentSize int) (*FileStrings, error) {
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if segmentSize <= fileStringsHeaderSize+fileStringsRecordSize || segmentSize%os.Getpagesize() != 0 || segmentSize > 1<<31 {
+		return nil, fmt.Errorf("segmentSize(%d) must be a multiple of the page size & <= 2GiB", segmentSize)
+	}
+
+	output := &FileStrings{}
+	var err error
+	if output.data, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, err
+	}
+	if output.offsetsFile, err = os.OpenFile(path+".off", os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, errors.Join(err, output.close())
+	}
+	if output.indexFile, err = os.OpenFile(path+".idx", os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, errors.Join(err, output.close())
+	}
+	// Close without syncing on failure, so a file that is not a dictionary is never overwritten
+	if err = output.load(uint64(segmentSize)); err != nil {
+		return nil, errors.Join(err, output.close())
+	}
+	return output, nil
+}
+
+func (i *FileStrings) Deduplicate(input string) string {
+	output, _ := i.Value(i.Insert(input))
+	return output
+}
+
+func (i *FileStrings) Insert(input string) uint64 {
+	uniqueID, err := i.TryInsert(input)
+	if err != nil {
+		log.Panicf("cannot intern %q: %v", input, err)
+	}
+	return uniqueID
+}
+
+// TryInsert is Insert, returning I/O errors instead of panicking
+func (i *FileStrings) TryInsert(input string) (uint64, error) {
+	hash := fnv1a(input)
+	slot, uniqueID := i.find(input, hash)
+	if uniqueID != 0 {
+		return uniqueID, nil
+	}
+
+	offset, err := i.appendData(input)
+	if err != nil {
+		return 0, err
+	}
+
+	uniqueID = uint64(len(i.offsets) + 1)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], offset)
+	if _, err = i.offsetsFile.WriteAt(buf[:], int64(len(i.offsets))*8); err != nil {
+		return 0, err
+	}
+	i.offsets = append(i.offsets, offset)
+
+	if uint64(len(i.offsets))*2 > uint64(len(i.index)) {
+		return uniqueID, i.rebuildIndex(uint64(len(i.index)) * 2)
+	}
+	return uniqueID, i.writeSlot(slot, uniqueID)
+}
+
+func (i *FileStrings) Value(uniqueID uint64) (output string, ok bool) {
+	if uniqueID == 0 || uniqueID > uint64(len(i.offsets)) {
+		return output, false
+	}
+
+	offset := i.offsets[uniqueID-1]
+	segment := i.segments[offset/i.segmentSize]
+	start := offset % i.segmentSize
+	size := uint64(binary.LittleEndian.Uint32(segment[start:]))
+	if size == 0 {
+		return "", true
+	}
+	start += fileStringsRecordSize
+	return unsafe.String(&segment[start], size), true
+}
+
+func (i *FileStrings) ID(input string) (uint64, bool) {
+	_, uniqueID := i.find(input, fnv1a(input))
+	return uniqueID, uniqueID != 0
+}
+
+func (i *FileStrings) Len() int {
+	return len(i.offsets)
+}
+
+// Clear deletes every value from the files & reset the counter back to 0.
+// It panics on I/O errors.
+func (i *FileStrings) Clear() {
+	if err := i.unmap(); err != nil {
+		log.Panicf("cannot clear: %v", err)
+	}
+	if err := errors.Join(i.data.Truncate(0), i.offsetsFile.Truncate(0), i.indexFile.Truncate(0)); err != nil {
+		log.Panicf("cannot clear: %v", err)
+	}
+	i.offsets = i.offsets[:0]
+	if err := i.load(i.segmentSize); err != nil {
+		log.Panicf("cannot clear: %v", err)
+	}
+}
+
+// Sync commits the appended values to stable storage, they are restored with the same unique ids when reopened.
+func (i *FileStrings) Sync() error {
+	if err := errors.Join(i.data.Sync(), i.offsetsFile.Sync(), i.indexFile.Sync()); err != nil {
+		return err
+	}
+	if err := i.writeHeader(); err != nil {
+		return err
+	}
+	return i.data.Sync()
+}
+
+// Close syncs & closes the files, & unmaps the data file.
+func (i *FileStrings) Close() error {
+	if i.data == nil {
+		return os.ErrClosed
+	}
+	return errors.Join(i.Sync(), i.close())
+}
+
+// close unmaps the data file & closes the files
+func (i *FileStrings) close() error {
+	err := i.unmap()
+	for _, file := range []*os.File{i.data, i.offsetsFile, i.indexFile} {
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+	}
+	i.data, i.offsetsFile, i.indexFile = nil, nil, nil
+	return err
+}
+
+// load reads the header, offsets & index from the files, initializing them if the data file is empty
+func (i *FileStrings) load(segmentSize uint64) error {
+	info, err := i.data.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, fileStringsHeaderSize)
+	var count uint64
+	if info.Size() == 0 {
+		i.segmentSize = segmentSize
+		i.dataEnd = fileStringsHeaderSize
+		if err = i.writeHeader(); err != nil {
+			return err
+		}
+	} else {
+		if _, err = i.data.ReadAt(header, 0); err != nil {
+			return fmt.Errorf("%w: %v", ErrFileStringsFormat, err)
+		}
+		if string(header[:8]) != fileStringsMagic || binary.LittleEndian.Uint32(header[8:]) != fileStringsVersion {
+			return ErrFileStringsFormat
+		}
+		i.segmentSize = uint64(binary.LittleEndian.Uint32(header[12:]))
+		count = binary.LittleEndian.Uint64(header[16:])
+		i.dataEnd = binary.LittleEndian.Uint64(header[24:])
+		if i.segmentSize == 0 || i.dataEnd < fileStringsHeaderSize || i.dataEnd > uint64(info.Size()) {
+			return ErrFileStringsFormat
+		}
+	}
+
+	// Map every segment holding data, plus the one being appended to
+	for uint64(len(i.segments))*i.segmentSize < i.dataEnd || len(i.segments) == 0 {
+		if err = i.mapSegment(); err != nil {
+			return err
+		}
+	}
+
+	offsetsInfo, err := i.offsetsFile.Stat()
+	if err != nil {
+		return err
+	}
+	if count > uint64(offsetsInfo.Size())/8 {
+		return fmt.Errorf("%w: %d offsets are missing", ErrFileStringsFormat, count-uint64(offsetsInfo.Size())/8)
+	}
+	offsets := make([]byte, count*8)
+	if _, err = i.offsetsFile.ReadAt(offsets, 0); err != nil && !(errors.Is(err, io.EOF) && count == 0) {
+		return fmt.Errorf("%w: offsets: %v", ErrFileStringsFormat, err)
+	}
+	i.offsets = i.offsets[:0]
+	for index := uint64(0); index < count; index++ {
+		offset := binary.LittleEndian.Uint64(offsets[index*8:])
+		if offset < fileStringsHeaderSize || offset+fileStringsRecordSize > i.dataEnd ||
+			offset%i.segmentSize+fileStringsRecordSize > i.segmentSize {
+			return fmt.Errorf("%w: offset %d out of range", ErrFileStringsFormat, offset)
+		}
+		// Value trusts the record length, so it must not reach past the record's segment or the data
+		start := offset % i.segmentSize
+		size := uint64(binary.LittleEndian.Uint32(i.segments[offset/i.segmentSize][start:]))
+		if start+fileStringsRecordSize+size > i.segmentSize || offset+fileStringsRecordSize+size > i.dataEnd {
+			return fmt.Errorf("%w: record %d at offset %d is truncated", ErrFileStringsFormat, index+1, offset)
+		}
+		i.offsets = append(i.offsets, offset)
+	}
+
+	if err = i.loadIndex(); err != nil {
+		return i.rebuildIndex(max(fileStringsMinCapacity, nextPowerOfTwo(count*2)))
+	}
+	return nil
+}
+
+// loadIndex reads the hash index, ignoring entries appended after the last Sync
+func (i *FileStrings) loadIndex() error {
+	header := make([]byte, fileStringsIndexHeader)
+	if _, err := i.indexFile.ReadAt(header, 0); err != nil {
+		return err
+	}
+	capacity := binary.LittleEndian.Uint64(header[8:])
+	if string(header[:8]) != fileStringsIndexMagic || capacity < fileStringsMinCapacity || capacity&(capacity-1) != 0 ||
+		capacity < uint64(len(i.offsets))*2 {
+		return ErrFileStringsFormat
+	}
+
+	slots := make([]byte, capacity*8)
+	if _, err := i.indexFile.ReadAt(slots, fileStringsIndexHeader); err != nil {
+		return err
+	}
+	i.index = make([]uint64, capacity)
+	found := 0
+	for slot := range i.index {
+		uniqueID := binary.LittleEndian.Uint64(slots[slot*8:])
+		if uniqueID > uint64(len(i.offsets)) {
+			// The index is rebuilt rather than patched, because dropping a slot may break a probe sequence
+			return ErrFileStringsFormat
+		}
+		if uniqueID != 0 {
+			found++
+		}
+		i.index[slot] = uniqueID
+	}
+	if found != len(i.offsets) {
+		return ErrFileStringsFormat
+	}
+	return nil
+}
+
+// rebuildIndex rewrites the hash index with the capacity from the offsets
+func (i *FileStrings) rebuildIndex(capacity uint64) error {
+	i.index = make([]uint64, capacity)
+	for index := range i.offsets {
+		uniqueID := uint64(index + 1)
+		value, _ := i.Value(uniqueID)
+		slot, _ := i.find(value, fnv1a(value))
+		i.index[slot] = uniqueID
+	}
+
+	buf := make([]byte, fileStringsIndexHeader+capacity*8)
+	copy(buf, fileStringsIndexMagic)
+	binary.LittleEndian.PutUint64(buf[8:], capacity)
+	for slot, uniqueID := range i.index {
+		binary.LittleEndian.PutUint64(buf[fileStringsIndexHeader+slot*8:], uniqueID)
+	}
+	if err := i.indexFile.Truncate(0); err != nil {
+		return err
+	}
+	_, err := i.indexFile.WriteAt(buf, 0)
+	return err
+}
+
+// find returns the slot holding the input, or the empty slot where it belongs along with a zero unique id
+func (i *FileStrings) find(input string, hash uint64) (slot uint64, uniqueID uint64) {
+	mask := uint64(len(i.index) - 1)
+	for slot = hash & mask; ; slot = (slot + 1) & mask {
+		uniqueID = i.index[slot]
+		if uniqueID == 0 {
+			return slot, 0
+		}
+		if value, _ := i.Value(uniqueID); value == input {
+			return slot, uniqueID
+		}
+	}
+}
+
+func (i *FileStrings) writeSlot(slot, uniqueID uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uniqueID)
+	if _, err := i.indexFile.WriteAt(buf[:], int64(fileStringsIndexHeader+slot*8)); err != nil {
+		return err
+	}
+	i.index[slot] = uniqueID
+	return nil
+}
+
+// appendData writes a record to the data file & returns its offset, starting a new segment if it does not fit
+func (i *FileStrings) appendData(input string) (uint64, error) {
+	size := uint64(fileStringsRecordSize + len(input))
+	if size > i.segmentSize {
+		return 0, fmt.Errorf("%w: %d bytes > %d bytes segments", ErrValueTooLarge, len(input), i.segmentSize-fileStringsRecordSize)
+	}
+
+	offset := i.dataEnd
+	if offset%i.segmentSize+size > i.segmentSize {
+		offset = (offset/i.segmentSize + 1) * i.segmentSize
+	}
+	// The previous record may have filled its segment exactly, so map up to the one holding this record
+	for offset/i.segmentSize >= uint64(len(i.segments)) {
+		if err := i.mapSegment(); err != nil {
+			return 0, err
+		}
+	}
+
+	i.scratch = binary.LittleEndian.AppendUint32(i.scratch[:0], uint32(len(input)))
+	i.scratch = append(i.scratch, input...)
+	if _, err := i.data.WriteAt(i.scratch, int64(offset)); err != nil {
+		return 0, err
+	}
+	i.dataEnd = offset + size
+	return offset, nil
+}
+
+// mapSegment extends the data file by one segment & maps it read-only
+func (i *FileStrings) mapSegment() error {
+	end := int64(len(i.segments)+1) * int64(i.segmentSize)
+	info, err := i.data.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < end {
+		if err = i.data.Truncate(end); err != nil {
+			return err
+		}
+	}
+
+	segment, err := syscall.Mmap(int(i.data.Fd()), end-int64(i.segmentSize), int(i.segmentSize), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	i.segments = append(i.segments, segment)
+	return nil
+}
+
+func (i *FileStrings) unmap() error {
+	var err error
+	for _, segment := range i.segments {
+		err = errors.Join(err, syscall.Munmap(segment))
+	}
+	i.segments = nil
+	return err
+}
+
+func (i *FileStrings) writeHeader() error {
+	header := make([]byte, fileStringsHeaderSize)
+	copy(header, fileStringsMagic)
+	binary.LittleEndian.PutUint32(header[8:], fileStringsVersion)
+	binary.LittleEndian.PutUint32(header[12:], uint32(i.segmentSize))
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(i.offsets)))
+	binary.LittleEndian.PutUint64(header[24:], i.dataEnd)
+	_, err := i.data.WriteAt(header, 0)
+	return err
+}
+
+// fnv1a is the 64-bit FNV-1a hash, it is stable across processes so the on-disk index can be reused
+func fnv1a(input string) uint64 {
+	hash := uint64(14695981039346656037)
+	for index := 0; index < len(input); index++ {
+		hash ^= uint64(input[index])
+		hash *= 1099511628211
+	}
+	return hash
+}
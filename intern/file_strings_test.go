@@ -0,0 +1,121 @@
+//go:build linux || darwin
+
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStrings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary")
+	segmentSize := os.Getpagesize()
+
+	i, err := OpenFileStrings(path, segmentSize)
+	require.NoError(t, err)
+	require.Zero(t, i.Len())
+
+	output, ok := i.Value(0)
+	require.False(t, ok)
+	require.Zero(t, output)
+
+	index := i.Insert("value")
+	require.EqualValues(t, 1, index)
+	require.EqualValues(t, index, i.Insert("value"))
+	require.EqualValues(t, "value", i.Deduplicate("value"))
+	require.EqualValues(t, 2, i.Insert(""))
+
+	_, err = i.TryInsert(strings.Repeat("x", segmentSize))
+	require.ErrorIs(t, err, ErrValueTooLarge)
+
+	// Enough values to span several segments & grow the index
+	inputs := randomStringInputs(1_000)
+	ids := map[string]uint64{}
+	for _, input := range inputs {
+		ids[input] = i.Insert(input)
+	}
+	require.EqualValues(t, len(ids)+2, i.Len())
+	require.NoError(t, i.Close())
+	require.ErrorIs(t, i.Close(), os.ErrClosed)
+
+	i, err = OpenFileStrings(path, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids)+2, i.Len())
+	for input, uniqueID := range ids {
+		output, ok := i.Value(uniqueID)
+		require.True(t, ok)
+		require.EqualValues(t, input, output)
+
+		lookup, ok := i.ID(input)
+		require.True(t, ok)
+		require.EqualValues(t, uniqueID, lookup)
+	}
+	output, ok = i.Value(2)
+	require.True(t, ok)
+	require.Empty(t, output)
+
+	// Values appended after the last Sync are dropped when reopening without Close
+	require.NoError(t, i.Sync())
+	synced := i.Len()
+	i.Insert("unsynced")
+	require.NoError(t, i.close())
+
+	i, err = OpenFileStrings(path, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, synced, i.Len())
+	_, ok = i.ID("unsynced")
+	require.False(t, ok)
+	require.EqualValues(t, synced+1, i.Insert("new value"))
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	require.EqualValues(t, 1, i.Insert("after clear"))
+	require.NoError(t, i.Close())
+
+	require.NoError(t, os.WriteFile(path, []byte("not a dictionary"), 0o644))
+	_, err = OpenFileStrings(path, 0)
+	require.ErrorIs(t, err, ErrFileStringsFormat)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.EqualValues(t, "not a dictionary", string(data))
+}
+
+func TestFileStrings_segmentBoundary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary")
+	segmentSize := os.Getpagesize()
+
+	i, err := OpenFileStrings(path, segmentSize)
+	require.NoError(t, err)
+
+	// Fill the first segment exactly, so the next record starts on a segment boundary
+	fill := strings.Repeat("x", segmentSize-fileStringsHeaderSize-fileStringsRecordSize)
+	require.EqualValues(t, 1, i.Insert(fill))
+	require.EqualValues(t, 2, i.Insert("next"))
+
+	output, ok := i.Value(2)
+	require.True(t, ok)
+	require.EqualValues(t, "next", output)
+	require.EqualValues(t, fill, i.Deduplicate(fill))
+	require.NoError(t, i.Close())
+}
+
+func TestFileStrings_corruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary")
+	i, err := OpenFileStrings(path, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, i.Insert("value"))
+	require.NoError(t, i.Close())
+
+	// Grow the record length past the end of the data
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte{0xff, 0xff, 0xff, 0x7f}, fileStringsHeaderSize)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	_, err = OpenFileStrings(path, 0)
+	require.ErrorIs(t, err, ErrFileStringsFormat)
+}
@@ -0,0 +1,193 @@
+package intern
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+)
+
+// ErrHashCollision is returned by Freeze when two distinct values have the same hash
+var ErrHashCollision = errors.New("intern: hash collision")
+
+// FrozenIntern is a read-only intern table, it is safe for concurrent use without any locking.
+// Insert & Deduplicate return the existing entry for values present when the table was frozen, & panic for unseen values.
+// Clear panics.
+type FrozenIntern[T comparable] interface {
+	GenericIntern[T]
+	Lookup[T]
+	Ranger[T]
+}
+
+var _ FrozenIntern[int] = &frozenIntern[int]{}
+
+const (
+	// frozenBucketSize is the average number of keys per bucket of the perfect hash
+	frozenBucketSize = 4
+	// frozenMaxSeeds is the number of seeds tried before giving up on building the perfect hash
+	frozenMaxSeeds = 16
+	// frozenMaxDisplacement is the number of displacements tried per bucket for each seed
+	frozenMaxDisplacement = 1 << 16
+)
+
+// frozenIntern implements the FrozenIntern interface with a minimal perfect hash (hash & displace) from value to slot,
+// & dense value storage indexed by `uniqueID-1`.
+type frozenIntern[T comparable] struct {
+	values  []T
+	present []bool
+	count   int
+
+	hash          func(T) uint64
+	seed          uint64
+	displacements []uint64
+	slots         []uint64
+}
+
+// Freeze builds a read-only copy of the src intern table, keeping the same unique ids.
+// The hash must return the same hash for equal values, & distinct hashes for distinct values: an ErrHashCollision error
+// is returned otherwise.
+func Freeze[T comparable](src Ranger[T], hash func(T) uint64) (FrozenIntern[T], error) {
+	if nil == hash {
+		log.Panic("hash is required for Freeze")
+	}
+
+	output := &frozenIntern[T]{hash: hash}
+	var hashes []uint64
+	var ids []uint64
+	src.Range(func(uniqueID uint64, value T) bool {
+		if uniqueID > uint64(len(output.values)) {
+			output.values = append(output.values, make([]T, uniqueID-uint64(len(output.values)))...)
+			output.present = append(output.present, make([]bool, uniqueID-uint64(len(output.present)))...)
+		}
+		output.values[uniqueID-1] = value
+		output.present[uniqueID-1] = true
+		hashes = append(hashes, hash(value))
+		ids = append(ids, uniqueID)
+		return true
+	})
+	output.count = len(ids)
+
+	seen := make(map[uint64]uint64, len(hashes))
+	for index, h := range hashes {
+		if other, ok := seen[h]; ok {
+			return nil, fmt.Errorf("%w: unique ids %d & %d", ErrHashCollision, other, ids[index])
+		}
+		seen[h] = ids[index]
+	}
+
+	for seed := uint64(0); seed < frozenMaxSeeds; seed++ {
+		if output.build(seed, hashes, ids) {
+			return output, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: cannot build a perfect hash for %d values", ErrHashCollision, len(ids))
+}
+
+// build tries to place every hash in its own slot with the seed, returns false if a bucket cannot be displaced
+func (i *frozenIntern[T]) build(seed uint64, hashes, ids []uint64) bool {
+	n := uint64(len(hashes))
+	i.seed = seed
+	i.displacements = make([]uint64, max(1, n/frozenBucketSize))
+	i.slots = make([]uint64, n)
+	if n == 0 {
+		return true
+	}
+
+	buckets := make([][]int, len(i.displacements))
+	for index, h := range hashes {
+		bucket := mix(h, seed) % uint64(len(buckets))
+		buckets[bucket] = append(buckets[bucket], index)
+	}
+	order := make([]int, len(buckets))
+	for index := range order {
+		order[index] = index
+	}
+	// Place the largest buckets first, while most slots are still free
+	slices.SortStableFunc(order, func(a, b int) int { return len(buckets[b]) - len(buckets[a]) })
+
+	positions := make([]uint64, 0, frozenBucketSize*2)
+	for _, bucket := range order {
+		keys := buckets[bucket]
+		if len(keys) == 0 {
+			continue
+		}
+
+		placed := false
+		for displacement := uint64(1); displacement <= frozenMaxDisplacement && !placed; displacement++ {
+			positions = positions[:0]
+			placed = true
+			for _, key := range keys {
+				position := mix(hashes[key], seed^displacement<<32) % n
+				if i.slots[position] != 0 || slices.Contains(positions, position) {
+					placed = false
+					break
+				}
+				positions = append(positions, position)
+			}
+			if placed {
+				i.displacements[bucket] = displacement
+				for index, key := range keys {
+					i.slots[positions[index]] = ids[key]
+				}
+			}
+		}
+		if !placed {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *frozenIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)-1]
+}
+
+func (i *frozenIntern[T]) Insert(input T) uint64 {
+	uniqueID, ok := i.ID(input)
+	if !ok {
+		log.Panicf("cannot insert %v into a frozen intern", input)
+	}
+	return uniqueID
+}
+
+func (i *frozenIntern[T]) ID(input T) (uint64, bool) {
+	if i.count == 0 {
+		return 0, false
+	}
+
+	h := i.hash(input)
+	displacement := i.displacements[mix(h, i.seed)%uint64(len(i.displacements))]
+	uniqueID := i.slots[mix(h, i.seed^displacement<<32)%uint64(len(i.slots))]
+	// Values that were not frozen land on an arbitrary slot, so check the value
+	if i.values[uniqueID-1] != input {
+		return 0, false
+	}
+	return uniqueID, true
+}
+
+func (i *frozenIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	if uniqueID == 0 || uniqueID > uint64(len(i.values)) || !i.present[uniqueID-1] {
+		return output, false
+	}
+	return i.values[uniqueID-1], true
+}
+
+func (i *frozenIntern[T]) Len() int {
+	return i.count
+}
+
+func (i *frozenIntern[T]) Clear() {
+	log.Panic("cannot clear a frozen intern")
+}
+
+func (i *frozenIntern[T]) Range(yield func(uniqueID uint64, value T) bool) {
+	for index, value := range i.values {
+		if i.present[index] && !yield(uint64(index+1), value) {
+			return
+		}
+	}
+}
+
+func (i *frozenIntern[T]) All() func(yield func(uniqueID uint64, value T) bool) {
+	return i.Range
+}
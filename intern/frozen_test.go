@@ -0,0 +1,81 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"hash/maphash"
+	"sync"
+	"testing"
+)
+
+func TestFrozenIntern(t *testing.T) {
+	src := NewSafe[string]()
+	inputs := randomStringInputs(1_000)
+	ids := InsertAll(src, inputs, nil)
+
+	i, err := Freeze(src.(Ranger[string]), stringHash(maphash.MakeSeed()))
+	require.NoError(t, err)
+	require.EqualValues(t, src.Len(), i.Len())
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index, input := range inputs {
+				uniqueID, ok := i.ID(input)
+				assert.True(t, ok)
+				assert.EqualValues(t, ids[index], uniqueID)
+				assert.EqualValues(t, ids[index], i.Insert(input))
+				assert.EqualValues(t, input, i.Deduplicate(input))
+
+				output, ok := i.Value(ids[index])
+				assert.True(t, ok)
+				assert.EqualValues(t, input, output)
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, ok := i.ID("not frozen")
+	require.False(t, ok)
+	_, ok = i.Value(0)
+	require.False(t, ok)
+	require.Panics(t, func() { i.Insert("not frozen") })
+	require.Panics(t, i.Clear)
+
+	count := 0
+	i.Range(func(uniqueID uint64, value string) bool {
+		count++
+		output, _ := src.Value(uniqueID)
+		require.EqualValues(t, output, value)
+		return true
+	})
+	require.EqualValues(t, src.Len(), count)
+
+	empty, err := Freeze(New[string]().(Ranger[string]), stringHash(maphash.MakeSeed()))
+	require.NoError(t, err)
+	require.Zero(t, empty.Len())
+	_, ok = empty.ID("value")
+	require.False(t, ok)
+
+	_, err = Freeze(src.(Ranger[string]), func(string) uint64 { return 0 })
+	require.ErrorIs(t, err, ErrHashCollision)
+}
+
+func BenchmarkFrozenIntern(b *testing.B) {
+	inputs := randomStringInputs(10_000)
+
+	src := New[string]()
+	InsertAll(src, inputs, nil)
+	i, err := Freeze(src.(Ranger[string]), stringHash(maphash.MakeSeed()))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for index := 0; pb.Next(); index++ {
+			i.Deduplicate(inputs[index%len(inputs)])
+		}
+	})
+}
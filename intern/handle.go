@@ -0,0 +1,83 @@
+package intern
+
+import (
+	"log"
+)
+
+// Handle is a typed unique id issued by a Table[T], use it instead of a bare uint64 so ids of different value types
+// cannot be mixed up. To keep tables of the same value type apart, give them distinct types (e.g. `type MetricName string`).
+//
+// Handles are comparable & as cheap as a uint64. When built with the `interndebug` tag, a Handle also records the
+// table that issued it, & using it with another table panics.
+type Handle[T comparable] struct {
+	// brand comes first, a trailing zero sized field would be padded
+	brand    handleBrand
+	uniqueID uint64
+}
+
+// Raw returns the unique id of the handle, e.g. to serialize it
+func (h Handle[T]) Raw() uint64 {
+	return h.uniqueID
+}
+
+// IsZero returns true for the zero Handle, which is never issued by a table
+func (h Handle[T]) IsZero() bool {
+	return h.uniqueID == 0
+}
+
+// Table wraps a GenericIntern[T] to hand out typed Handle[T] instead of bare uint64 unique ids
+type Table[T comparable] struct {
+	intern GenericIntern[T]
+	brand  handleBrand
+}
+
+// NewTable creates a new Table[T] around the intern table, if intern is nil, then New[T]() is used.
+func NewTable[T comparable](intern GenericIntern[T]) *Table[T] {
+	if nil == intern {
+		intern = New[T]()
+	}
+	return &Table[T]{intern: intern, brand: newHandleBrand()}
+}
+
+// Deduplicate returns the interned value, see GenericIntern.Deduplicate
+func (t *Table[T]) Deduplicate(input T) T {
+	return t.intern.Deduplicate(input)
+}
+
+// Insert returns the handle of the input, inserting it if it is new
+func (t *Table[T]) Insert(input T) Handle[T] {
+	return Handle[T]{uniqueID: t.intern.Insert(input), brand: t.brand}
+}
+
+// Value returns the interned value of the handle.
+// In `interndebug` builds it panics if the handle was issued by another table.
+func (t *Table[T]) Value(handle Handle[T]) (output T, ok bool) {
+	t.check(handle)
+	return t.intern.Value(handle.uniqueID)
+}
+
+// FromRaw converts a unique id back into a handle of this table, e.g. to deserialize it
+func (t *Table[T]) FromRaw(uniqueID uint64) Handle[T] {
+	return Handle[T]{uniqueID: uniqueID, brand: t.brand}
+}
+
+// Len returns the number of interned values
+func (t *Table[T]) Len() int {
+	return t.intern.Len()
+}
+
+// Clear deletes the interned values, see GenericIntern.Clear
+func (t *Table[T]) Clear() {
+	t.intern.Clear()
+}
+
+// Intern returns the underlying intern table
+func (t *Table[T]) Intern() GenericIntern[T] {
+	return t.intern
+}
+
+func (t *Table[T]) check(handle Handle[T]) {
+	if handleDebug && !handle.IsZero() && handle.brand != t.brand {
+		log.Panicf("handle %d was issued by table %v, not by table %v", handle.uniqueID, handle.brand, t.brand)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build interndebug
+
+package intern
+
+import (
+	"sync/atomic"
+)
+
+// handleDebug enables the cross-table checks of Table[T]
+const handleDebug = true
+
+// handleBrand identifies the table that issued a Handle
+type handleBrand uint64
+
+var handleBrands atomic.Uint64
+
+func newHandleBrand() handleBrand {
+	return handleBrand(handleBrands.Add(1))
+}
@@ -0,0 +1,19 @@
+//go:build interndebug
+
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTable_debug(t *testing.T) {
+	metricNames := NewTable[string](nil)
+	labelValues := NewTable[string](nil)
+
+	handle := metricNames.Insert("requests_total")
+	labelValues.Insert("requests_total")
+
+	require.Panics(t, func() { labelValues.Value(handle) })
+	require.NotPanics(t, func() { labelValues.Value(labelValues.FromRaw(handle.Raw())) })
+}
@@ -0,0 +1,13 @@
+//go:build !interndebug
+
+package intern
+
+// handleDebug enables the cross-table checks of Table[T], build with the `interndebug` tag to turn them on
+const handleDebug = false
+
+// handleBrand is zero sized outside of `interndebug` builds, so a Handle is as small as a uint64
+type handleBrand struct{}
+
+func newHandleBrand() handleBrand {
+	return handleBrand{}
+}
@@ -0,0 +1,39 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"unsafe"
+)
+
+func TestTable(t *testing.T) {
+	type metricName string
+
+	table := NewTable[metricName](nil)
+	require.Zero(t, table.Len())
+
+	handle := table.Insert("requests_total")
+	require.False(t, handle.IsZero())
+	require.True(t, Handle[metricName]{}.IsZero())
+	require.Equal(t, handle, table.Insert("requests_total"))
+	require.NotEqual(t, handle, table.Insert("errors_total"))
+	require.EqualValues(t, "requests_total", table.Deduplicate("requests_total"))
+
+	output, ok := table.Value(handle)
+	require.True(t, ok)
+	require.EqualValues(t, "requests_total", output)
+
+	raw := handle.Raw()
+	require.EqualValues(t, 1, raw)
+	require.Equal(t, handle, table.FromRaw(raw))
+
+	_, ok = table.Value(Handle[metricName]{})
+	require.False(t, ok)
+
+	if !handleDebug {
+		require.EqualValues(t, 8, unsafe.Sizeof(handle))
+	}
+
+	table.Clear()
+	require.Zero(t, table.Intern().Len())
+}
@@ -0,0 +1,143 @@
+package intern
+
+import (
+	"log"
+)
+
+// AnyIntern is the GenericIntern interface for values that are not comparable, e.g. slices, maps or protobuf messages.
+// Equality is decided by the intern table (see NewBy & NewHashed) instead of the == operator.
+// A GenericIntern[T] also implements AnyIntern[T].
+type AnyIntern[T any] interface {
+	// Deduplicate returns the interned value equal to the input, inserting the input if it is new.
+	Deduplicate(input T) (output T)
+
+	// Insert attempts to insert the value to the map & returns its unique id.
+	Insert(input T) (uniqueID uint64)
+
+	// Value returns the interned value for the given unique id
+	Value(uniqueID uint64) (output T, ok bool)
+
+	// Len returns the size of the map (number of keys)
+	Len() int
+
+	// Clear deletes the interned values & reset the counter back to 0.
+	Clear()
+}
+
+var _ AnyIntern[[]int] = &byIntern[[]int, string]{}
+var _ AnyIntern[[]int] = &hashedIntern[[]int]{}
+var _ AnyIntern[int] = New[int]()
+
+// byIntern implements the AnyIntern interface by deduplicating on a comparable key extracted from each value
+type byIntern[T any, K comparable] struct {
+	keys    map[K]uint64
+	values  map[uint64]T
+	counter uint64
+	key     func(T) K
+}
+
+// NewBy creates a new AnyIntern[T] instance where two values are equal if key returns the same K for both.
+// e.g. NewBy(func(labels []string) string { return strings.Join(labels, "\x00") })
+func NewBy[T any, K comparable](key func(T) K) AnyIntern[T] {
+	if nil == key {
+		log.Panic("key is required for NewBy")
+	}
+	return &byIntern[T, K]{
+		keys:   map[K]uint64{},
+		values: map[uint64]T{},
+		key:    key,
+	}
+}
+
+func (i *byIntern[T, K]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)]
+}
+
+func (i *byIntern[T, K]) Insert(input T) uint64 {
+	key := i.key(input)
+	if uniqueId, ok := i.keys[key]; ok {
+		return uniqueId
+	}
+
+	i.counter++
+	uniqueId := i.counter
+	i.keys[key] = uniqueId
+	i.values[uniqueId] = input
+	return uniqueId
+}
+
+func (i *byIntern[T, K]) Value(uniqueID uint64) (output T, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *byIntern[T, K]) Len() int {
+	return len(i.values)
+}
+
+func (i *byIntern[T, K]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.counter = 0
+}
+
+// hashedIntern implements the AnyIntern interface by hash-consing: values are bucketed by hash & compared with equal
+type hashedIntern[T any] struct {
+	buckets map[uint64][]uint64
+	values  map[uint64]T
+	counter uint64
+	hash    func(T) uint64
+	equal   func(a, b T) bool
+}
+
+// NewHashed creates a new AnyIntern[T] instance where two values are equal if equal(a, b) returns true.
+// The hash must return the same hash for equal values, collisions are resolved by calling equal.
+func NewHashed[T any](hash func(T) uint64, equal func(a, b T) bool) AnyIntern[T] {
+	if nil == hash {
+		log.Panic("hash is required for NewHashed")
+	}
+	if nil == equal {
+		log.Panic("equal is required for NewHashed")
+	}
+	return &hashedIntern[T]{
+		buckets: map[uint64][]uint64{},
+		values:  map[uint64]T{},
+		hash:    hash,
+		equal:   equal,
+	}
+}
+
+func (i *hashedIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)]
+}
+
+func (i *hashedIntern[T]) Insert(input T) uint64 {
+	hash := i.hash(input)
+	bucket := i.buckets[hash]
+	for _, uniqueId := range bucket {
+		if i.equal(i.values[uniqueId], input) {
+			return uniqueId
+		}
+	}
+
+	i.counter++
+	uniqueId := i.counter
+	i.buckets[hash] = append(bucket, uniqueId)
+	i.values[uniqueId] = input
+	return uniqueId
+}
+
+func (i *hashedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *hashedIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *hashedIntern[T]) Clear() {
+	clear(i.buckets)
+	clear(i.values)
+	i.counter = 0
+}
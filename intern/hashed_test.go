@@ -0,0 +1,57 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"hash/maphash"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnyIntern(t *testing.T) {
+	seed := maphash.MakeSeed()
+	for name, i := range map[string]AnyIntern[[]string]{
+		"by": NewBy(func(labels []string) string {
+			return strings.Join(labels, "\x00")
+		}),
+		"hashed": NewHashed(func(labels []string) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			for _, label := range labels {
+				h.WriteString(label)
+				h.WriteByte(0)
+			}
+			return h.Sum64()
+		}, slices.Equal[[]string]),
+		"collisions": NewHashed(func([]string) uint64 {
+			return 0
+		}, slices.Equal[[]string]),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Zero(t, i.Len())
+			_, ok := i.Value(0)
+			require.False(t, ok)
+
+			labels := []string{"env", "prod"}
+			index := i.Insert(labels)
+			require.NotZero(t, index)
+			require.EqualValues(t, index, i.Insert([]string{"env", "prod"}))
+			require.NotEqualValues(t, index, i.Insert([]string{"env", "dev"}))
+			require.NotEqualValues(t, index, i.Insert([]string{"envprod"}))
+			require.EqualValues(t, 3, i.Len())
+
+			output := i.Deduplicate([]string{"env", "prod"})
+			require.EqualValues(t, labels, output)
+			require.Same(t, &labels[0], &output[0])
+
+			output, ok = i.Value(index)
+			require.True(t, ok)
+			require.EqualValues(t, labels, output)
+
+			i.Clear()
+			require.Zero(t, i.Len())
+			_, ok = i.Value(index)
+			require.False(t, ok)
+		})
+	}
+}
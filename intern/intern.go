@@ -37,14 +37,28 @@ type genericIntern[T comparable] struct {
 	keys    map[T]uint64
 	values  map[uint64]T
 	counter uint64
+	stats   internStats
+	sizer   Sizer[T]
+	hooks   []func(uniqueID uint64, value T)
+	// generation is bumped by Clear, so replicas can tell ids issued before & after it apart
+	generation uint64
 }
 
 // New creates a new GenericIntern[T] instance
 func New[T comparable]() GenericIntern[T] {
+	return NewSized[T](DefaultSizer[T]())
+}
+
+// NewSized creates a new GenericIntern[T] instance that estimates the Stats.Bytes footprint with the sizer
+func NewSized[T comparable](sizer Sizer[T]) GenericIntern[T] {
+	if nil == sizer {
+		sizer = DefaultSizer[T]()
+	}
 	return &genericIntern[T]{
 		keys:    map[T]uint64{},
 		values:  map[uint64]T{},
 		counter: 0,
+		sizer:   sizer,
 	}
 }
 
@@ -55,7 +69,9 @@ func (i *genericIntern[T]) Deduplicate(input T) T {
 }
 
 func (i *genericIntern[T]) Insert(input T) uint64 {
+	i.stats.inserts.Add(1)
 	if uniqueId, ok := i.keys[input]; ok {
+		i.stats.hits.Add(1)
 		return uniqueId
 	}
 
@@ -63,6 +79,11 @@ func (i *genericIntern[T]) Insert(input T) uint64 {
 	uniqueId := i.counter
 	i.keys[input] = uniqueId
 	i.values[uniqueId] = input
+	i.stats.misses.Add(1)
+	i.stats.add(1, entrySize(i.sizer, input))
+	for _, hook := range i.hooks {
+		hook(uniqueId, input)
+	}
 	return uniqueId
 }
 
@@ -72,13 +93,15 @@ func (i *genericIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
 }
 
 func (i *genericIntern[T]) Len() int {
-	return int(i.counter)
+	return len(i.values)
 }
 
 func (i *genericIntern[T]) Clear() {
 	maps.Clear(i.keys)
 	maps.Clear(i.values)
 	i.counter = 0
+	i.generation++
+	i.stats.reset()
 }
 
 // safeGeneric wraps the genericIntern struct & makes it thread safe
@@ -92,6 +115,11 @@ func NewSafe[T comparable]() GenericIntern[T] {
 	return &safeGeneric[T]{intern: New[T]()}
 }
 
+// NewSafeSized creates a new thread safe GenericIntern[T] instance that estimates the Stats.Bytes footprint with the sizer
+func NewSafeSized[T comparable](sizer Sizer[T]) GenericIntern[T] {
+	return &safeGeneric[T]{intern: NewSized[T](sizer)}
+}
+
 func (i *safeGeneric[T]) Deduplicate(input T) (output T) {
 	i.RWMutex.Lock()
 	output = i.intern.Deduplicate(input)
@@ -0,0 +1,90 @@
+package intern
+
+import (
+	"maps"
+	"slices"
+)
+
+// Lookup is implemented by intern tables that can find the unique id of a value without inserting it
+type Lookup[T comparable] interface {
+	// ID returns the unique id of the input if it is present in the map, otherwise `ok=false` is returned.
+	ID(input T) (uniqueID uint64, ok bool)
+}
+
+// Ranger is implemented by intern tables that can enumerate their entries in unique id order
+type Ranger[T comparable] interface {
+	// Range calls yield for every entry in unique id order, until yield returns false.
+	Range(yield func(uniqueID uint64, value T) bool)
+
+	// All returns an iterator over every entry in unique id order, it is compatible with iter.Seq2[uint64, T].
+	All() func(yield func(uniqueID uint64, value T) bool)
+}
+
+var _ Lookup[int] = &genericIntern[int]{}
+var _ Ranger[int] = &genericIntern[int]{}
+var _ Lookup[int] = &safeGeneric[int]{}
+var _ Ranger[int] = &safeGeneric[int]{}
+
+func (i *genericIntern[T]) ID(input T) (uniqueID uint64, ok bool) {
+	uniqueID, ok = i.keys[input]
+	return uniqueID, ok
+}
+
+// Range calls yield for every entry in unique id order, the intern table must not be modified during the iteration.
+func (i *genericIntern[T]) Range(yield func(uniqueID uint64, value T) bool) {
+	// unique ids are assigned sequentially from the counter, so walk the counter instead of sorting the map keys,
+	// unless a restored snapshot left gaps in the ids
+	if uint64(len(i.values)) != i.counter {
+		for _, uniqueID := range i.sortedIDs() {
+			if !yield(uniqueID, i.values[uniqueID]) {
+				return
+			}
+		}
+		return
+	}
+	for uniqueID := uint64(1); uniqueID <= i.counter; uniqueID++ {
+		if !yield(uniqueID, i.values[uniqueID]) {
+			return
+		}
+	}
+}
+
+// sortedIDs returns the unique ids in ascending order
+func (i *genericIntern[T]) sortedIDs() []uint64 {
+	ids := slices.Collect(maps.Keys(i.values))
+	slices.Sort(ids)
+	return ids
+}
+
+func (i *genericIntern[T]) All() func(yield func(uniqueID uint64, value T) bool) {
+	return i.Range
+}
+
+func (i *safeGeneric[T]) ID(input T) (uniqueID uint64, ok bool) {
+	i.RWMutex.RLock()
+	uniqueID, ok = i.intern.(Lookup[T]).ID(input)
+	i.RWMutex.RUnlock()
+	return uniqueID, ok
+}
+
+// Range calls yield for every entry in unique id order.
+// The entries are copied under the read lock before iterating, so yield sees a consistent snapshot & may modify the intern table.
+func (i *safeGeneric[T]) Range(yield func(uniqueID uint64, value T) bool) {
+	i.RWMutex.RLock()
+	entries := make([]Entry[T], 0, i.intern.Len())
+	i.intern.(Ranger[T]).Range(func(uniqueID uint64, value T) bool {
+		entries = append(entries, Entry[T]{ID: uniqueID, Value: value})
+		return true
+	})
+	i.RWMutex.RUnlock()
+
+	for _, entry := range entries {
+		if !yield(entry.ID, entry.Value) {
+			return
+		}
+	}
+}
+
+func (i *safeGeneric[T]) All() func(yield func(uniqueID uint64, value T) bool) {
+	return i.Range
+}
@@ -0,0 +1,101 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestIterate(t *testing.T) {
+	for name, i := range map[string]GenericIntern[string]{
+		"generic": New[string](),
+		"safe":    NewSafe[string](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			lookup := i.(Lookup[string])
+			ranger := i.(Ranger[string])
+
+			_, ok := lookup.ID("value")
+			require.False(t, ok)
+			require.Zero(t, i.Len())
+
+			inputs := []string{"c", "a", "b", "a"}
+			ids := InsertAll(i, inputs, nil)
+			for index, input := range inputs {
+				uniqueID, ok := lookup.ID(input)
+				require.True(t, ok)
+				require.EqualValues(t, ids[index], uniqueID)
+			}
+
+			var outputs []string
+			var outputIDs []uint64
+			ranger.All()(func(uniqueID uint64, value string) bool {
+				outputIDs = append(outputIDs, uniqueID)
+				outputs = append(outputs, value)
+				return true
+			})
+			require.EqualValues(t, []uint64{1, 2, 3}, outputIDs)
+			require.EqualValues(t, []string{"c", "a", "b"}, outputs)
+
+			outputs = outputs[:0]
+			ranger.Range(func(_ uint64, value string) bool {
+				outputs = append(outputs, value)
+				return len(outputs) < 2
+			})
+			require.EqualValues(t, []string{"c", "a"}, outputs)
+
+			i.Clear()
+			_, ok = lookup.ID("a")
+			require.False(t, ok)
+			ranger.Range(func(uint64, string) bool {
+				require.Fail(t, "unexpected entry")
+				return true
+			})
+		})
+	}
+}
+
+func TestIterate_safeSnapshot(t *testing.T) {
+	i := NewSafe[string]()
+	i.Insert("a")
+
+	var outputs []string
+	i.(Ranger[string]).Range(func(_ uint64, value string) bool {
+		i.Insert(value + value)
+		outputs = append(outputs, value)
+		return true
+	})
+	require.EqualValues(t, []string{"a"}, outputs)
+	require.EqualValues(t, 2, i.Len())
+}
+
+func TestIterate_gaps(t *testing.T) {
+	for name, i := range map[string]GenericIntern[string]{
+		"generic": New[string](),
+		"safe":    NewSafe[string](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// snapshots may skip ids, e.g. after entries were dropped before the snapshot was written
+			i.(snapshotter[string]).restore(5, []uint64{2, 5}, []string{"b", "e"})
+
+			var outputIDs []uint64
+			var outputs []string
+			i.(Ranger[string]).Range(func(uniqueID uint64, value string) bool {
+				outputIDs = append(outputIDs, uniqueID)
+				outputs = append(outputs, value)
+				return true
+			})
+			require.EqualValues(t, []uint64{2, 5}, outputIDs)
+			require.EqualValues(t, []string{"b", "e"}, outputs)
+			require.EqualValues(t, 2, i.Len())
+
+			// the ids are not walked one by one
+			i.(snapshotter[string]).restore(1<<40, []uint64{1, 1 << 40}, []string{"a", "z"})
+			outputIDs = outputIDs[:0]
+			i.(Ranger[string]).Range(func(uniqueID uint64, _ string) bool {
+				outputIDs = append(outputIDs, uniqueID)
+				return true
+			})
+			require.EqualValues(t, []uint64{1, 1 << 40}, outputIDs)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package intern
+
+import (
+	"fmt"
+)
+
+// Merge inserts every entry of src into dst, & returns the remap table from source unique ids to destination unique ids:
+// `remap[srcID] == dstID`. Unused source ids, including 0, map to 0.
+func Merge[T comparable](dst GenericIntern[T], src Ranger[T]) (remap []uint64) {
+	src.Range(func(uniqueID uint64, value T) bool {
+		if uniqueID >= uint64(len(remap)) {
+			remap = append(remap, make([]uint64, uniqueID+1-uint64(len(remap)))...)
+		}
+		remap[uniqueID] = dst.Insert(value)
+		return true
+	})
+	return remap
+}
+
+// Remap rewrites every unique id in place through a remap table returned by Merge.
+// If a unique id is not in the remap table, then an ErrUnknownID error is returned & the remaining ids are left unchanged.
+func Remap(uniqueIDs []uint64, remap []uint64) error {
+	for index, uniqueID := range uniqueIDs {
+		if uniqueID >= uint64(len(remap)) || remap[uniqueID] == 0 {
+			return fmt.Errorf("%w: %d at index %d", ErrUnknownID, uniqueID, index)
+		}
+		uniqueIDs[index] = remap[uniqueID]
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	dst := NewSafe[string]()
+	InsertAll(dst, []string{"a", "b"}, nil)
+
+	src := New[string]()
+	srcIDs := InsertAll(src, []string{"c", "a", "d", "c"}, nil)
+
+	remap := Merge(dst, src.(Ranger[string]))
+	require.EqualValues(t, []uint64{0, 3, 1, 4}, remap)
+	require.EqualValues(t, 4, dst.Len())
+
+	require.NoError(t, Remap(srcIDs, remap))
+	outputs, err := Values(dst, srcIDs, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"c", "a", "d", "c"}, outputs)
+
+	ids := []uint64{1, 5, 2}
+	require.ErrorIs(t, Remap(ids, remap), ErrUnknownID)
+	require.EqualValues(t, []uint64{3, 5, 2}, ids)
+	require.ErrorIs(t, Remap([]uint64{0}, remap), ErrUnknownID)
+
+	require.Empty(t, Merge(dst, New[string]().(Ranger[string])))
+}
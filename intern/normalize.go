@@ -0,0 +1,112 @@
+package intern
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"log"
+	"strings"
+)
+
+// NormalizedForm selects which form of a value a normalizing intern table stores
+type NormalizedForm int
+
+const (
+	// KeepFirstSeen stores the first value inserted for each normalized form, e.g. "Foo" if "Foo" was inserted before "foo"
+	KeepFirstSeen NormalizedForm = iota
+	// KeepNormalized stores the normalized form, e.g. "foo" for case folding
+	KeepNormalized
+)
+
+// NFC normalizes a string to Unicode Normalization Form C (canonical composition), e.g. "é" to "é"
+func NFC(input string) string {
+	return norm.NFC.String(input)
+}
+
+// NFKC normalizes a string to Unicode Normalization Form KC (compatibility composition), e.g. "ﬁ" to "fi"
+func NFKC(input string) string {
+	return norm.NFKC.String(input)
+}
+
+// CaseFold folds the case of a string for caseless matching, e.g. "Foo" to "foo"
+func CaseFold(input string) string {
+	// cases.Caser is stateful, so a new one is needed for every call to be safe for concurrent use
+	return cases.Fold().String(input)
+}
+
+// TrimSpace removes leading & trailing white space
+func TrimSpace(input string) string {
+	return strings.TrimSpace(input)
+}
+
+// Normalizers chains the normalizers, applying them in order
+func Normalizers[T any](normalizers ...func(T) T) func(T) T {
+	return func(input T) T {
+		for _, normalize := range normalizers {
+			input = normalize(input)
+		}
+		return input
+	}
+}
+
+var _ GenericIntern[string] = &normalizedIntern[string]{}
+
+// normalizedIntern implements the GenericIntern interface by deduplicating on the normalized form of each value
+type normalizedIntern[T comparable] struct {
+	keys      map[T]uint64
+	values    map[uint64]T
+	counter   uint64
+	normalize func(T) T
+	form      NormalizedForm
+}
+
+// NewNormalized creates a new GenericIntern[T] instance where values with the same normalized form share one entry.
+// e.g. NewNormalized(Normalizers(TrimSpace, NFC, CaseFold), KeepFirstSeen)
+func NewNormalized[T comparable](normalize func(T) T, form NormalizedForm) GenericIntern[T] {
+	if nil == normalize {
+		log.Panic("normalize is required for NewNormalized")
+	}
+	if form != KeepFirstSeen && form != KeepNormalized {
+		log.Panicf("unknown normalized form %d", form)
+	}
+	return &normalizedIntern[T]{
+		keys:      map[T]uint64{},
+		values:    map[uint64]T{},
+		normalize: normalize,
+		form:      form,
+	}
+}
+
+func (i *normalizedIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)]
+}
+
+func (i *normalizedIntern[T]) Insert(input T) uint64 {
+	key := i.normalize(input)
+	if uniqueId, ok := i.keys[key]; ok {
+		return uniqueId
+	}
+
+	if i.form == KeepNormalized {
+		input = key
+	}
+	i.counter++
+	uniqueId := i.counter
+	i.keys[key] = uniqueId
+	i.values[uniqueId] = input
+	return uniqueId
+}
+
+func (i *normalizedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *normalizedIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *normalizedIntern[T]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.counter = 0
+}
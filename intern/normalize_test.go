@@ -0,0 +1,45 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	require.EqualValues(t, "é", NFC("é"))
+	require.EqualValues(t, "fi", NFKC("ﬁ"))
+	require.EqualValues(t, "strasse", CaseFold("STRASSE"))
+	require.EqualValues(t, "foo", TrimSpace(" foo\t"))
+	require.EqualValues(t, "été", Normalizers(TrimSpace, NFC, CaseFold)(" ÉTÉ "))
+}
+
+func TestNormalizedIntern(t *testing.T) {
+	normalize := Normalizers(TrimSpace, NFC, CaseFold)
+
+	t.Run("first seen", func(t *testing.T) {
+		i := NewNormalized(normalize, KeepFirstSeen)
+		index := i.Insert("Foo")
+		require.EqualValues(t, index, i.Insert("foo"))
+		require.EqualValues(t, index, i.Insert(" FOO "))
+		require.EqualValues(t, "Foo", i.Deduplicate("foo"))
+
+		accent := i.Insert("café")
+		require.EqualValues(t, accent, i.Insert("café"))
+		require.NotEqualValues(t, index, accent)
+		require.EqualValues(t, 2, i.Len())
+
+		i.Clear()
+		require.Zero(t, i.Len())
+		require.EqualValues(t, "foo", i.Deduplicate("foo"))
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		i := NewNormalized(normalize, KeepNormalized)
+		index := i.Insert(" Café ")
+		require.EqualValues(t, index, i.Insert("CAFÉ"))
+
+		output, ok := i.Value(index)
+		require.True(t, ok)
+		require.EqualValues(t, "café", output)
+	})
+}
@@ -0,0 +1,111 @@
+package intern
+
+import (
+	"log"
+	"math/bits"
+)
+
+// overlayBit marks the unique ids issued by an overlay, parents never reach 2^63 entries so the ranges are disjoint
+const overlayBit = 1 << 63
+
+// overlayMaxDepth is the deepest overlay of overlays, an overlay at depth d issues ids below 2^(63-d)
+const overlayMaxDepth = 16
+
+var _ GenericIntern[int] = &overlayIntern[int]{}
+var _ Lookup[int] = &overlayIntern[int]{}
+
+// overlayIntern implements the GenericIntern interface on top of a shared parent table.
+// Values present in the parent resolve to the parent's unique ids, new values are only inserted locally.
+//
+// An overlay at depth d tags its local ids with d leading one bits followed by a zero bit,
+// so an overlay of overlays issues ids disjoint from every ancestor's.
+type overlayIntern[T comparable] struct {
+	parent GenericIntern[T]
+	lookup Lookup[T]
+	local  GenericIntern[T]
+	depth  int
+	prefix uint64
+}
+
+// NewOverlay creates a new GenericIntern[T] instance that reads through to the parent, e.g. a shared vocabulary
+// from Freeze or NewSafe, & inserts values missing from the parent locally with unique ids >= 2^63.
+// The parent must implement Lookup[T] & is never modified, so Clear only deletes the local values & the overlay
+// can be put back into a sync.Pool.
+// The parent may itself be an overlay, up to 16 levels deep, whose local ids are kept apart from the new overlay's.
+//
+// The overlay is not safe for concurrent use, even if the parent is.
+func NewOverlay[T comparable](parent GenericIntern[T]) GenericIntern[T] {
+	lookup, ok := parent.(Lookup[T])
+	if !ok {
+		log.Panicf("parent %T must implement Lookup", parent)
+	}
+	depth := 1
+	if overlay, ok := parent.(*overlayIntern[T]); ok {
+		depth = overlay.depth + 1
+	}
+	if depth > overlayMaxDepth {
+		log.Panicf("overlays can be at most %d levels deep", overlayMaxDepth)
+	}
+	return &overlayIntern[T]{
+		parent: parent,
+		lookup: lookup,
+		local:  New[T](),
+		depth:  depth,
+		prefix: ^uint64(0) << (64 - depth),
+	}
+}
+
+func (i *overlayIntern[T]) Deduplicate(input T) T {
+	output, _ := i.Value(i.Insert(input))
+	return output
+}
+
+func (i *overlayIntern[T]) Insert(input T) uint64 {
+	if uniqueID, ok := i.ID(input); ok {
+		return uniqueID
+	}
+	local := i.local.Insert(input)
+	if local >= 1<<(63-i.depth) {
+		log.Panicf("overlay at depth %d is full", i.depth)
+	}
+	return local | i.prefix
+}
+
+// ID returns the local unique id of the input if it was inserted locally, otherwise its unique id in the parent.
+// Local values win, so an id stays stable for the lifetime of the overlay even if the parent learns the value later.
+func (i *overlayIntern[T]) ID(input T) (uint64, bool) {
+	if uniqueID, ok := i.local.(Lookup[T]).ID(input); ok {
+		return uniqueID | i.prefix, true
+	}
+	return i.lookup.ID(input)
+}
+
+func (i *overlayIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	// the leading one bits are the depth of the overlay that issued the id, 0 for the root table
+	switch depth := bits.LeadingZeros64(^uniqueID); {
+	case depth == i.depth:
+		return i.local.Value(uniqueID &^ i.prefix)
+	case depth < i.depth:
+		return i.parent.Value(uniqueID)
+	default:
+		return output, false
+	}
+}
+
+// Len returns the number of distinct values visible through the overlay: the parent's & the local ones it does not hold.
+// It checks every local value against the parent, since a shared parent may learn a value after it was inserted locally.
+func (i *overlayIntern[T]) Len() int {
+	output := i.parent.Len()
+	i.local.(Ranger[T]).Range(func(_ uint64, value T) bool {
+		if _, ok := i.lookup.ID(value); !ok {
+			output++
+		}
+		return true
+	})
+	return output
+}
+
+// Clear deletes the local values, the parent is left untouched
+func (i *overlayIntern[T]) Clear() {
+	i.local.Clear()
+}
@@ -0,0 +1,96 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"hash/maphash"
+	"strconv"
+	"testing"
+)
+
+func TestOverlayIntern(t *testing.T) {
+	parent := NewSafe[string]()
+	shared := parent.Insert("shared")
+
+	frozen, err := Freeze(parent.(Ranger[string]), stringHash(maphash.MakeSeed()))
+	require.NoError(t, err)
+
+	for name, parent := range map[string]GenericIntern[string]{"safe": parent, "frozen": frozen} {
+		t.Run(name, func(t *testing.T) {
+			i := NewOverlay(parent)
+			require.EqualValues(t, 1, i.Len())
+			require.EqualValues(t, shared, i.Insert("shared"))
+
+			local := i.Insert("local")
+			require.NotZero(t, local&overlayBit)
+			require.EqualValues(t, local, i.Insert("local"))
+			require.EqualValues(t, "local", i.Deduplicate("local"))
+			require.EqualValues(t, 2, i.Len())
+			require.EqualValues(t, 1, parent.Len())
+
+			output, ok := i.Value(shared)
+			require.True(t, ok)
+			require.EqualValues(t, "shared", output)
+
+			_, ok = parent.Value(local)
+			require.False(t, ok)
+
+			i.Clear()
+			require.EqualValues(t, 1, i.Len())
+			_, ok = i.Value(local)
+			require.False(t, ok)
+			require.EqualValues(t, shared, i.Insert("shared"))
+		})
+	}
+
+	require.Panics(t, func() { NewOverlay(NewBounded[string](1, LRU, nil)) })
+}
+
+func TestOverlayIntern_parentLearnsLocal(t *testing.T) {
+	parent := NewSafe[string]()
+	parent.Insert("shared")
+
+	i := NewOverlay(parent)
+	local := i.Insert("local")
+	require.EqualValues(t, 2, i.Len())
+
+	parent.Insert("local")
+	require.EqualValues(t, local, i.Insert("local"))
+	require.EqualValues(t, 2, i.Len())
+	require.EqualValues(t, 2, parent.Len())
+}
+
+func TestOverlayIntern_nested(t *testing.T) {
+	base := NewSafe[string]()
+	shared := base.Insert("shared")
+
+	mid := NewOverlay(base)
+	a := mid.Insert("a")
+	leaf := NewOverlay(mid)
+	require.EqualValues(t, shared, leaf.Insert("shared"))
+	require.EqualValues(t, a, leaf.Insert("a"))
+
+	b := leaf.Insert("b")
+	require.NotEqualValues(t, a, b)
+	require.NotZero(t, b&overlayBit)
+	for uniqueID, value := range map[uint64]string{shared: "shared", a: "a", b: "b"} {
+		output, ok := leaf.Value(uniqueID)
+		require.True(t, ok)
+		require.EqualValues(t, value, output)
+	}
+	_, ok := mid.Value(b)
+	require.False(t, ok)
+	require.EqualValues(t, 3, leaf.Len())
+
+	// a sibling overlay of the same depth reuses the leaf's ids for its own values
+	sibling := NewOverlay(mid)
+	require.EqualValues(t, b, sibling.Insert("c"))
+
+	deep := GenericIntern[string](base)
+	for range overlayMaxDepth {
+		deep = NewOverlay(deep)
+		output, ok := deep.Value(deep.Insert(strconv.Itoa(deep.Len())))
+		require.True(t, ok)
+		require.EqualValues(t, strconv.Itoa(deep.Len()-1), output)
+	}
+	require.Panics(t, func() { NewOverlay(deep) })
+}
@@ -0,0 +1,77 @@
+package intern
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"log"
+)
+
+var _ AnyIntern[proto.Message] = &protoIntern{}
+
+// protoIntern implements the AnyIntern interface for protobuf messages of a single type.
+// Messages are keyed on their deterministic wire encoding, so byte-identical messages share one canonical copy.
+type protoIntern struct {
+	keys       map[string]uint64
+	values     map[uint64]proto.Message
+	counter    uint64
+	descriptor protoreflect.MessageDescriptor
+	options    proto.MarshalOptions
+	buf        []byte
+}
+
+// NewProto creates a new AnyIntern[proto.Message] instance for messages of the descriptor's type,
+// following the same descriptor conventions as functions.AllocProto.
+//
+// Inserted messages are cloned, & the canonical message returned by Deduplicate or Value is shared
+// by every caller: it must be treated as read-only.
+// Insert panics if the message is of another type, or cannot be marshaled.
+func NewProto(descriptor protoreflect.Message) AnyIntern[proto.Message] {
+	if nil == descriptor {
+		log.Panic("descriptor is required for NewProto")
+	}
+	return &protoIntern{
+		keys:       map[string]uint64{},
+		values:     map[uint64]proto.Message{},
+		descriptor: descriptor.Descriptor(),
+		options:    proto.MarshalOptions{Deterministic: true, AllowPartial: true},
+	}
+}
+
+func (i *protoIntern) Deduplicate(input proto.Message) proto.Message {
+	return i.values[i.Insert(input)]
+}
+
+func (i *protoIntern) Insert(input proto.Message) uint64 {
+	if name := input.ProtoReflect().Descriptor().FullName(); name != i.descriptor.FullName() {
+		log.Panicf("cannot intern %s in a %s intern", name, i.descriptor.FullName())
+	}
+
+	var err error
+	if i.buf, err = i.options.MarshalAppend(i.buf[:0], input); err != nil {
+		log.Panicf("cannot intern %s: %v", i.descriptor.FullName(), err)
+	}
+	if uniqueId, ok := i.keys[string(i.buf)]; ok {
+		return uniqueId
+	}
+
+	i.counter++
+	uniqueId := i.counter
+	i.keys[string(i.buf)] = uniqueId
+	i.values[uniqueId] = proto.Clone(input)
+	return uniqueId
+}
+
+func (i *protoIntern) Value(uniqueID uint64) (output proto.Message, ok bool) {
+	output, ok = i.values[uniqueID]
+	return output, ok
+}
+
+func (i *protoIntern) Len() int {
+	return len(i.values)
+}
+
+func (i *protoIntern) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.counter = 0
+}
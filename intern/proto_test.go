@@ -0,0 +1,45 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+func TestProtoIntern(t *testing.T) {
+	var exampleMsg structpb.Struct
+	i := NewProto(exampleMsg.ProtoReflect())
+	require.Zero(t, i.Len())
+
+	newStruct := func(fields map[string]any) proto.Message {
+		msg, err := structpb.NewStruct(fields)
+		require.NoError(t, err)
+		return msg
+	}
+
+	input := newStruct(map[string]any{"env": "prod", "region": "us", "replicas": 3})
+	index := i.Insert(input)
+	require.NotZero(t, index)
+	require.EqualValues(t, index, i.Insert(newStruct(map[string]any{"replicas": 3, "region": "us", "env": "prod"})))
+	require.NotEqualValues(t, index, i.Insert(newStruct(map[string]any{"env": "dev"})))
+	require.EqualValues(t, 2, i.Len())
+
+	output := i.Deduplicate(newStruct(map[string]any{"env": "prod", "region": "us", "replicas": 3}))
+	require.True(t, proto.Equal(input, output))
+	require.NotSame(t, input, output)
+
+	value, ok := i.Value(index)
+	require.True(t, ok)
+	require.Same(t, output, value)
+
+	input.(*structpb.Struct).Fields["env"] = structpb.NewStringValue("mutated")
+	require.EqualValues(t, "prod", output.(*structpb.Struct).Fields["env"].GetStringValue())
+
+	require.Panics(t, func() { i.Insert(structpb.NewStringValue("value")) })
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	_, ok = i.Value(index)
+	require.False(t, ok)
+}
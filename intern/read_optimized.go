@@ -0,0 +1,175 @@
+package intern
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// ReadOptimizedIntern is a GenericIntern for read-mostly workloads, whose hits never take a lock
+type ReadOptimizedIntern[T comparable] interface {
+	GenericIntern[T]
+	Lookup[T]
+
+	// Merge publishes the write buffer in a new snapshot, so every value inserted so far can be read without locking,
+	// e.g. once a warm-up phase is done, instead of waiting for the buffer to reach the merge threshold.
+	Merge()
+}
+
+var _ ReadOptimizedIntern[int] = &readOptimized[int]{}
+
+// readOptimized implements the ReadOptimizedIntern interface.
+// Lookups read an immutable snapshot published through an atomic pointer without any locking,
+// while misses go to a small write buffer under a mutex. Once the buffer is large enough, it is merged
+// into a new snapshot by copying, so the copy cost is amortized over the inserts since the last merge.
+type readOptimized[T comparable] struct {
+	snapshot atomic.Pointer[cowSnapshot[T]]
+
+	sync.Mutex
+	keys           map[T]uint64
+	values         []T
+	mergeThreshold int
+}
+
+// cowSnapshot is an immutable view of the merged entries, values are indexed by `uniqueID-1`
+type cowSnapshot[T comparable] struct {
+	keys   map[T]uint64
+	values []T
+}
+
+// NewReadOptimized creates a new ReadOptimizedIntern[T] instance that is safe for concurrent use, with lock-free hits.
+// The write buffer is merged into the snapshot once it holds max(mergeThreshold, entries/8) values,
+// if mergeThreshold <= 0, then 64 is used.
+func NewReadOptimized[T comparable](mergeThreshold int) ReadOptimizedIntern[T] {
+	if mergeThreshold <= 0 {
+		mergeThreshold = 64
+	}
+	output := &readOptimized[T]{keys: map[T]uint64{}, mergeThreshold: mergeThreshold}
+	output.snapshot.Store(&cowSnapshot[T]{keys: map[T]uint64{}})
+	return output
+}
+
+func (i *readOptimized[T]) Deduplicate(input T) T {
+	snapshot := i.snapshot.Load()
+	if uniqueID, ok := snapshot.keys[input]; ok {
+		return snapshot.values[uniqueID-1]
+	}
+
+	i.Lock()
+	defer i.Unlock()
+	uniqueID := i.insert(input)
+	output, _ := i.value(uniqueID)
+	return output
+}
+
+func (i *readOptimized[T]) Insert(input T) uint64 {
+	if uniqueID, ok := i.snapshot.Load().keys[input]; ok {
+		return uniqueID
+	}
+
+	i.Lock()
+	defer i.Unlock()
+	return i.insert(input)
+}
+
+func (i *readOptimized[T]) ID(input T) (uint64, bool) {
+	if uniqueID, ok := i.snapshot.Load().keys[input]; ok {
+		return uniqueID, true
+	}
+
+	i.Lock()
+	defer i.Unlock()
+	// The buffer may have been merged since the snapshot was loaded
+	if uniqueID, ok := i.snapshot.Load().keys[input]; ok {
+		return uniqueID, true
+	}
+	uniqueID, ok := i.keys[input]
+	return uniqueID, ok
+}
+
+func (i *readOptimized[T]) Value(uniqueID uint64) (output T, ok bool) {
+	snapshot := i.snapshot.Load()
+	if uniqueID != 0 && uniqueID <= uint64(len(snapshot.values)) {
+		return snapshot.values[uniqueID-1], true
+	}
+
+	i.Lock()
+	defer i.Unlock()
+	return i.value(uniqueID)
+}
+
+func (i *readOptimized[T]) Len() int {
+	i.Lock()
+	defer i.Unlock()
+	return len(i.snapshot.Load().values) + len(i.values)
+}
+
+func (i *readOptimized[T]) Clear() {
+	i.Lock()
+	i.snapshot.Store(&cowSnapshot[T]{keys: map[T]uint64{}})
+	clear(i.keys)
+	clear(i.values)
+	i.values = i.values[:0]
+	i.Unlock()
+}
+
+func (i *readOptimized[T]) Merge() {
+	i.Lock()
+	i.merge()
+	i.Unlock()
+}
+
+// insert adds the input to the write buffer, the caller must hold the lock
+func (i *readOptimized[T]) insert(input T) uint64 {
+	snapshot := i.snapshot.Load()
+	if uniqueID, ok := snapshot.keys[input]; ok {
+		return uniqueID
+	}
+	if uniqueID, ok := i.keys[input]; ok {
+		return uniqueID
+	}
+
+	i.values = append(i.values, input)
+	uniqueID := uint64(len(snapshot.values) + len(i.values))
+	i.keys[input] = uniqueID
+
+	if len(i.values) >= max(i.mergeThreshold, len(snapshot.values)/8) {
+		i.merge()
+	}
+	return uniqueID
+}
+
+// value returns the value from the snapshot or the write buffer, the caller must hold the lock
+func (i *readOptimized[T]) value(uniqueID uint64) (output T, ok bool) {
+	snapshot := i.snapshot.Load()
+	if uniqueID == 0 {
+		return output, false
+	}
+	if uniqueID <= uint64(len(snapshot.values)) {
+		return snapshot.values[uniqueID-1], true
+	}
+	if index := uniqueID - uint64(len(snapshot.values)) - 1; index < uint64(len(i.values)) {
+		return i.values[index], true
+	}
+	return output, false
+}
+
+// merge copies the snapshot & the write buffer into a new snapshot, the caller must hold the lock
+func (i *readOptimized[T]) merge() {
+	if len(i.values) == 0 {
+		return
+	}
+
+	snapshot := i.snapshot.Load()
+	keys := make(map[T]uint64, len(snapshot.keys)+len(i.keys))
+	maps.Copy(keys, snapshot.keys)
+	maps.Copy(keys, i.keys)
+	values := make([]T, 0, len(snapshot.values)+len(i.values))
+	values = append(append(values, snapshot.values...), i.values...)
+
+	// Publish before emptying the buffer, so concurrent readers always find the value in one or the other
+	i.snapshot.Store(&cowSnapshot[T]{keys: keys, values: values})
+	clear(i.keys)
+	clear(i.values)
+	i.values = i.values[:0]
+}
@@ -0,0 +1,93 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestReadOptimized(t *testing.T) {
+	i := NewReadOptimized[string](4)
+	require.Zero(t, i.Len())
+
+	output, ok := i.Value(0)
+	require.False(t, ok)
+	require.Zero(t, output)
+
+	index := i.Insert("value")
+	require.EqualValues(t, 1, index)
+	require.EqualValues(t, index, i.Insert("value"))
+
+	output, ok = i.Value(index)
+	require.True(t, ok)
+	require.EqualValues(t, "value", output)
+
+	i.Merge()
+	require.Empty(t, i.(*readOptimized[string]).values)
+	uniqueID, ok := i.ID("value")
+	require.True(t, ok)
+	require.EqualValues(t, index, uniqueID)
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	_, ok = i.Value(index)
+	require.False(t, ok)
+
+	inputs := randomStringInputs(1_000)
+	unique := make(map[string]string, len(inputs))
+	for _, k := range inputs {
+		unique[k] = k
+	}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, input := range inputs {
+				assert.EqualValues(t, input, i.Deduplicate(input))
+				uniqueID := i.Insert(input)
+				output, ok := i.Value(uniqueID)
+				assert.True(t, ok)
+				assert.EqualValues(t, input, output)
+			}
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, len(unique), i.Len())
+
+	ids := map[uint64]string{}
+	for _, input := range inputs {
+		ids[i.Insert(input)] = input
+	}
+	require.Len(t, ids, len(unique))
+}
+
+// BenchmarkSafeInternParallel & BenchmarkReadOptimizedParallel compare the scaling of a hit-heavy workload,
+// run them with e.g. `-cpu 1,2,4,8`
+func BenchmarkSafeInternParallel(b *testing.B) {
+	benchmarkParallelHits(b, NewSafe[string]())
+}
+
+func BenchmarkReadOptimizedParallel(b *testing.B) {
+	benchmarkParallelHits(b, NewReadOptimized[string](0))
+}
+
+func benchmarkParallelHits(b *testing.B, i GenericIntern[string]) {
+	inputs := randomStringInputs(10_000)
+	InsertAll(i, inputs, nil)
+	misses := randomStringInputs(10_000)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for index := 0; pb.Next(); index++ {
+			// 1 in 1000 lookups is a (likely) miss
+			if index%1_000 == 0 {
+				i.Deduplicate(misses[index/1_000%len(misses)])
+				continue
+			}
+			i.Deduplicate(inputs[index%len(inputs)])
+		}
+	})
+}
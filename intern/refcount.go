@@ -0,0 +1,139 @@
+package intern
+
+import (
+	"log"
+	"math"
+)
+
+// RefCountedIntern is a GenericIntern where every Insert (or Deduplicate) takes a reference on the value.
+// Values are removed once every reference has been handed back with Release, instead of growing until Clear.
+type RefCountedIntern[T comparable] interface {
+	GenericIntern[T]
+
+	// Release drops a reference on the unique id.
+	// When the reference count reaches zero, then the value is removed from the map & the id becomes invalid.
+	// If the unique id is not present (already released, stale generation or never issued), then `ok=false` is returned.
+	Release(uniqueID uint64) (ok bool)
+}
+
+var _ RefCountedIntern[int] = &refCountedIntern[int]{}
+
+const (
+	// refSlotBits is the number of low bits of a recycled unique id that hold the slot, the high bits hold the generation.
+	refSlotBits = 32
+	refSlotMask = 1<<refSlotBits - 1
+)
+
+// refCountedIntern implements the RefCountedIntern interface for any comparable type
+type refCountedIntern[T comparable] struct {
+	keys    map[T]uint64
+	values  map[uint64]refEntry[T]
+	counter uint64
+
+	// free holds released unique ids whose slot can be recycled under the next generation, only used when reuse=true.
+	free  []uint64
+	reuse bool
+
+	// generation is the generation of fresh slots, Clear raises it past maxGeneration so ids from before Clear stay stale.
+	generation    uint64
+	maxGeneration uint64
+}
+
+// refEntry is the interned value & the number of outstanding references on it
+type refEntry[T comparable] struct {
+	value T
+	refs  uint64
+}
+
+// NewRefCounted creates a new RefCountedIntern[T] instance.
+// If reuseIDs is true, then released ids are recycled: the low 32 bits of an id are its slot & the high 32 bits its generation,
+// so a stale id from a previous generation (or from before Clear) reports `ok=false` instead of aliasing the new value.
+// Insert panics once 2^32-1 slots have been assigned since the last Clear.
+// If reuseIDs is false, then ids are assigned sequentially & never handed out twice until Clear.
+func NewRefCounted[T comparable](reuseIDs bool) RefCountedIntern[T] {
+	return &refCountedIntern[T]{
+		keys:   map[T]uint64{},
+		values: map[uint64]refEntry[T]{},
+		reuse:  reuseIDs,
+	}
+}
+
+func (i *refCountedIntern[T]) Deduplicate(input T) T {
+	uniqueId := i.Insert(input)
+	return i.values[uniqueId].value
+}
+
+func (i *refCountedIntern[T]) Insert(input T) uint64 {
+	if uniqueId, ok := i.keys[input]; ok {
+		entry := i.values[uniqueId]
+		entry.refs++
+		i.values[uniqueId] = entry
+		return uniqueId
+	}
+
+	uniqueId := i.nextID()
+	i.keys[input] = uniqueId
+	i.values[uniqueId] = refEntry[T]{value: input, refs: 1}
+	return uniqueId
+}
+
+func (i *refCountedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	entry, ok := i.values[uniqueID]
+	return entry.value, ok
+}
+
+func (i *refCountedIntern[T]) Release(uniqueID uint64) bool {
+	entry, ok := i.values[uniqueID]
+	if !ok {
+		return false
+	}
+
+	entry.refs--
+	if entry.refs > 0 {
+		i.values[uniqueID] = entry
+		return true
+	}
+
+	delete(i.keys, entry.value)
+	delete(i.values, uniqueID)
+	// Retire the slot once its generation is exhausted, rather than wrapping around to an id that may still be held.
+	if i.reuse && uniqueID>>refSlotBits < math.MaxUint32 {
+		i.free = append(i.free, uniqueID)
+	}
+	return true
+}
+
+func (i *refCountedIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *refCountedIntern[T]) Clear() {
+	clear(i.keys)
+	clear(i.values)
+	i.free = i.free[:0]
+	i.counter = 0
+	if i.reuse {
+		if i.maxGeneration == math.MaxUint32 {
+			log.Panic("reference counted intern has exhausted its generations")
+		}
+		i.maxGeneration++
+		i.generation = i.maxGeneration
+	}
+}
+
+// nextID returns a recycled slot under its next generation if available, otherwise it increments the counter.
+func (i *refCountedIntern[T]) nextID() uint64 {
+	if n := len(i.free); n > 0 {
+		released := i.free[n-1]
+		i.free = i.free[:n-1]
+		generation := released>>refSlotBits + 1
+		i.maxGeneration = max(i.maxGeneration, generation)
+		return generation<<refSlotBits | released&refSlotMask
+	}
+
+	if i.reuse && i.counter == refSlotMask {
+		log.Panic("reference counted intern has exhausted its slots")
+	}
+	i.counter++
+	return i.generation<<refSlotBits | i.counter
+}
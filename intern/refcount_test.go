@@ -0,0 +1,91 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRefCountedIntern(t *testing.T) {
+	t.Run("release", func(t *testing.T) {
+		i := NewRefCounted[string](false)
+		require.Zero(t, i.Len())
+
+		index := i.Insert("value")
+		require.NotZero(t, index)
+		require.EqualValues(t, index, i.Insert("value"))
+		require.EqualValues(t, 1, i.Len())
+
+		require.True(t, i.Release(index))
+		output, ok := i.Value(index)
+		require.True(t, ok)
+		require.EqualValues(t, output, "value")
+
+		require.True(t, i.Release(index))
+		output, ok = i.Value(index)
+		require.False(t, ok)
+		require.Zero(t, output)
+		require.Zero(t, i.Len())
+		require.False(t, i.Release(index))
+
+		index2 := i.Insert("value")
+		require.NotEqualValues(t, index, index2)
+	})
+
+	t.Run("reuse", func(t *testing.T) {
+		i := NewRefCounted[string](true)
+
+		index := i.Insert("a")
+		require.True(t, i.Release(index))
+
+		index2 := i.Insert("b")
+		require.NotEqualValues(t, index, index2)
+		require.EqualValues(t, index&refSlotMask, index2&refSlotMask)
+
+		_, ok := i.Value(index)
+		require.False(t, ok)
+		require.False(t, i.Release(index))
+
+		output, ok := i.Value(index2)
+		require.True(t, ok)
+		require.EqualValues(t, output, "b")
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		i := NewRefCounted[string](false)
+		for _, input := range randomStringInputs(1_00) {
+			require.EqualValues(t, i.Deduplicate(input), input)
+		}
+		require.NotZero(t, i.Len())
+
+		i.Clear()
+		require.Zero(t, i.Len())
+		require.EqualValues(t, 1, i.Insert("value"))
+	})
+
+	t.Run("clear reuse", func(t *testing.T) {
+		i := NewRefCounted[string](true)
+		index := i.Insert("a")
+		released := i.Insert("b")
+		require.True(t, i.Release(released))
+		recycled := i.Insert("c")
+
+		i.Clear()
+		require.Zero(t, i.Len())
+		for _, input := range []string{"d", "e", "f"} {
+			index2 := i.Insert(input)
+			require.NotContains(t, []uint64{index, released, recycled}, index2)
+		}
+		for _, stale := range []uint64{index, released, recycled} {
+			_, ok := i.Value(stale)
+			require.False(t, ok)
+			require.False(t, i.Release(stale))
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		i := NewRefCounted[string](true)
+		i.(*refCountedIntern[string]).counter = refSlotMask - 1
+		require.EqualValues(t, refSlotMask, i.Insert("a"))
+		require.Panics(t, func() { i.Insert("b") })
+	})
+}
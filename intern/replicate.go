@@ -0,0 +1,137 @@
+package intern
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReplication is returned by Apply when a delta does not line up with the unique ids of the intern table
+var ErrReplication = errors.New("intern: replication out of sync")
+
+// Entry is a unique id & its interned value
+type Entry[T comparable] struct {
+	ID    uint64
+	Value T
+}
+
+// Delta is a batch of entries produced by Replicator.Since
+type Delta[T comparable] struct {
+	// Generation is the generation of the writer the entries were read from
+	Generation uint64
+	Entries    []Entry[T]
+}
+
+// Replicator is implemented by intern tables that can stream their new entries to replicas.
+// A writer ships `Since(watermark)` batches, & a reader applies them with Apply to rebuild an identical table.
+type Replicator[T comparable] interface {
+	// OnInsert registers a hook called after every new value is inserted.
+	// For thread safe tables the hook is called while holding the write lock, so it must not call back into the table.
+	OnInsert(hook func(uniqueID uint64, value T))
+
+	// Since appends every entry with a unique id > watermark to dst, in unique id order.
+	Since(watermark uint64, dst []Entry[T]) Delta[T]
+
+	// Generation returns the generation of the unique ids: it is bumped by every Clear,
+	// or it is the generation of the deltas applied to a replica.
+	Generation() uint64
+}
+
+// replica is implemented by the intern tables Apply can replicate into
+type replica interface {
+	Generation() uint64
+	// adopt sets the generation of an empty table to the generation of the writer it replicates
+	adopt(generation uint64)
+}
+
+var _ Replicator[int] = &genericIntern[int]{}
+var _ Replicator[int] = &safeGeneric[int]{}
+var _ replica = &genericIntern[int]{}
+var _ replica = &safeGeneric[int]{}
+
+// Apply inserts the delta produced by Replicator.Since into dst, which must have been created by New or NewSafe.
+// Entries that are already present with the same unique id are skipped, so deltas can be applied more than once.
+// If an entry would be assigned another unique id, then an ErrReplication error is returned & the rest of the delta is not applied.
+//
+// If the writer was cleared since dst was last updated, then the ids no longer line up & an ErrReplication error is returned:
+// clear dst & apply `Since(0)` to rebuild it.
+func Apply[T comparable](dst GenericIntern[T], delta Delta[T]) error {
+	target, ok := dst.(replica)
+	if !ok {
+		return fmt.Errorf("%w: %T does not track generations", ErrReplication, dst)
+	}
+	if generation := target.Generation(); generation != delta.Generation {
+		if dst.Len() > 0 {
+			return fmt.Errorf("%w: the writer is at generation %d, not %d", ErrReplication, delta.Generation, generation)
+		}
+		target.adopt(delta.Generation)
+	}
+
+	for _, entry := range delta.Entries {
+		if value, ok := dst.Value(entry.ID); ok {
+			if value != entry.Value {
+				return fmt.Errorf("%w: unique id %d is %v, not %v", ErrReplication, entry.ID, value, entry.Value)
+			}
+			continue
+		}
+		if uint64(dst.Len())+1 != entry.ID {
+			return fmt.Errorf("%w: expected unique id %d, got %d", ErrReplication, dst.Len()+1, entry.ID)
+		}
+		if uniqueID := dst.Insert(entry.Value); uniqueID != entry.ID {
+			return fmt.Errorf("%w: %v was inserted as unique id %d, not %d", ErrReplication, entry.Value, uniqueID, entry.ID)
+		}
+	}
+	return nil
+}
+
+func (i *genericIntern[T]) OnInsert(hook func(uniqueID uint64, value T)) {
+	i.hooks = append(i.hooks, hook)
+}
+
+func (i *genericIntern[T]) Since(watermark uint64, dst []Entry[T]) Delta[T] {
+	// unless a restored snapshot left gaps in the ids, walk the counter instead of sorting the map keys
+	if uint64(len(i.values)) != i.counter {
+		for _, uniqueID := range i.sortedIDs() {
+			if uniqueID > watermark {
+				dst = append(dst, Entry[T]{ID: uniqueID, Value: i.values[uniqueID]})
+			}
+		}
+	} else {
+		for uniqueID := watermark + 1; uniqueID <= i.counter; uniqueID++ {
+			dst = append(dst, Entry[T]{ID: uniqueID, Value: i.values[uniqueID]})
+		}
+	}
+	return Delta[T]{Generation: i.generation, Entries: dst}
+}
+
+func (i *genericIntern[T]) Generation() uint64 {
+	return i.generation
+}
+
+func (i *genericIntern[T]) adopt(generation uint64) {
+	i.generation = generation
+}
+
+func (i *safeGeneric[T]) OnInsert(hook func(uniqueID uint64, value T)) {
+	i.RWMutex.Lock()
+	i.intern.(Replicator[T]).OnInsert(hook)
+	i.RWMutex.Unlock()
+}
+
+func (i *safeGeneric[T]) Since(watermark uint64, dst []Entry[T]) Delta[T] {
+	i.RWMutex.RLock()
+	delta := i.intern.(Replicator[T]).Since(watermark, dst)
+	i.RWMutex.RUnlock()
+	return delta
+}
+
+func (i *safeGeneric[T]) Generation() uint64 {
+	i.RWMutex.RLock()
+	defer i.RWMutex.RUnlock()
+	return i.intern.(Replicator[T]).Generation()
+}
+
+func (i *safeGeneric[T]) adopt(generation uint64) {
+	i.RWMutex.Lock()
+	i.intern.(replica).adopt(generation)
+	i.RWMutex.Unlock()
+}
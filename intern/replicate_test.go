@@ -0,0 +1,60 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReplicate(t *testing.T) {
+	writer := NewSafe[string]()
+	var inserted []Entry[string]
+	writer.(Replicator[string]).OnInsert(func(uniqueID uint64, value string) {
+		inserted = append(inserted, Entry[string]{ID: uniqueID, Value: value})
+	})
+
+	InsertAll(writer, []string{"a", "b", "a"}, nil)
+	require.EqualValues(t, []Entry[string]{{ID: 1, Value: "a"}, {ID: 2, Value: "b"}}, inserted)
+
+	reader := New[string]()
+	delta := writer.(Replicator[string]).Since(0, nil)
+	require.EqualValues(t, inserted, delta.Entries)
+	require.NoError(t, Apply(reader, delta))
+
+	watermark := uint64(reader.Len())
+	InsertAll(writer, []string{"c", "b", "d"}, nil)
+	delta = writer.(Replicator[string]).Since(watermark, nil)
+	require.EqualValues(t, []Entry[string]{{ID: 3, Value: "c"}, {ID: 4, Value: "d"}}, delta.Entries)
+	require.EqualValues(t, inserted[2:], delta.Entries)
+
+	require.NoError(t, Apply(reader, delta))
+	require.NoError(t, Apply(reader, delta))
+	require.EqualValues(t, writer.(Replicator[string]).Since(0, nil), reader.(Replicator[string]).Since(0, nil))
+
+	require.ErrorIs(t, Apply(reader, Delta[string]{Entries: []Entry[string]{{ID: 1, Value: "z"}}}), ErrReplication)
+	require.ErrorIs(t, Apply(reader, Delta[string]{Entries: []Entry[string]{{ID: 6, Value: "f"}}}), ErrReplication)
+	require.ErrorIs(t, Apply(reader, Delta[string]{Entries: []Entry[string]{{ID: 5, Value: "a"}}}), ErrReplication)
+	require.ErrorIs(t, Apply(NewBounded[string](1, LRU, nil), delta), ErrReplication)
+}
+
+func TestReplicate_clear(t *testing.T) {
+	writer := NewSafe[string]()
+	InsertAll(writer, []string{"a", "b", "c", "d"}, nil)
+	reader := New[string]()
+	require.NoError(t, Apply(reader, writer.(Replicator[string]).Since(0, nil)))
+	watermark := uint64(reader.Len())
+
+	writer.Clear()
+	InsertAll(writer, []string{"w", "x", "y", "z", "v"}, nil)
+	require.ErrorIs(t, Apply(reader, writer.(Replicator[string]).Since(watermark, nil)), ErrReplication)
+
+	// an empty delta still reports the clear
+	writer.Clear()
+	require.ErrorIs(t, Apply(reader, writer.(Replicator[string]).Since(watermark, nil)), ErrReplication)
+
+	reader.Clear()
+	reader.Clear()
+	InsertAll(writer, []string{"w"}, nil)
+	require.NoError(t, Apply(reader, writer.(Replicator[string]).Since(0, nil)))
+	require.EqualValues(t, writer.(Replicator[string]).Generation(), reader.(Replicator[string]).Generation())
+	require.EqualValues(t, writer.(Replicator[string]).Since(0, nil), reader.(Replicator[string]).Since(0, nil))
+}
@@ -0,0 +1,144 @@
+package intern
+
+import (
+	"log"
+	"runtime"
+	"sync"
+)
+
+var _ GenericIntern[int] = &shardedIntern[int]{}
+
+// shardedIntern implements the GenericIntern interface by partitioning keys across independently locked shards.
+// Unique ids encode the shard they live in, so Value(id) goes straight to the owning shard without scanning.
+//
+// The id layout is `local*len(shards) + shard + 1`, which keeps ids globally unique & non-zero.
+type shardedIntern[T comparable] struct {
+	shards []internShard[T]
+	hash   func(T) uint64
+}
+
+// internShard is a single lock-striped partition of a shardedIntern
+type internShard[T comparable] struct {
+	sync.RWMutex
+	keys   map[T]uint64
+	values []T
+}
+
+// NewSharded creates a new GenericIntern[T] instance that is safe for concurrent use.
+// Keys are spread across `shards` partitions by `hash`, each guarded by its own lock.
+// If shards <= 0, then runtime.GOMAXPROCS(0) shards are used.
+func NewSharded[T comparable](shards int, hash func(T) uint64) GenericIntern[T] {
+	if nil == hash {
+		log.Panic("hash is required for NewSharded")
+	}
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+
+	output := &shardedIntern[T]{
+		shards: make([]internShard[T], shards),
+		hash:   hash,
+	}
+	for index := range output.shards {
+		output.shards[index].keys = map[T]uint64{}
+	}
+	return output
+}
+
+func (i *shardedIntern[T]) Deduplicate(input T) (output T) {
+	index, shard := i.shard(input)
+
+	shard.RLock()
+	if uniqueID, ok := shard.keys[input]; ok {
+		output = shard.values[i.local(uniqueID)]
+		shard.RUnlock()
+		return output
+	}
+	shard.RUnlock()
+
+	shard.Lock()
+	uniqueID := i.insert(index, shard, input)
+	output = shard.values[i.local(uniqueID)]
+	shard.Unlock()
+	return output
+}
+
+func (i *shardedIntern[T]) Insert(input T) (uniqueID uint64) {
+	index, shard := i.shard(input)
+
+	shard.RLock()
+	uniqueID, ok := shard.keys[input]
+	shard.RUnlock()
+	if ok {
+		return uniqueID
+	}
+
+	shard.Lock()
+	uniqueID = i.insert(index, shard, input)
+	shard.Unlock()
+	return uniqueID
+}
+
+func (i *shardedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	if uniqueID == 0 {
+		return output, false
+	}
+
+	shard := &i.shards[(uniqueID-1)%uint64(len(i.shards))]
+	local := i.local(uniqueID)
+
+	shard.RLock()
+	if local < uint64(len(shard.values)) {
+		output, ok = shard.values[local], true
+	}
+	shard.RUnlock()
+	return output, ok
+}
+
+func (i *shardedIntern[T]) Len() (output int) {
+	for index := range i.shards {
+		shard := &i.shards[index]
+		shard.RLock()
+		output += len(shard.values)
+		shard.RUnlock()
+	}
+	return output
+}
+
+func (i *shardedIntern[T]) Clear() {
+	for index := range i.shards {
+		i.shards[index].Lock()
+	}
+	for index := range i.shards {
+		shard := &i.shards[index]
+		clear(shard.keys)
+		clear(shard.values)
+		shard.values = shard.values[:0]
+	}
+	for index := range i.shards {
+		i.shards[index].Unlock()
+	}
+}
+
+// shard returns the partition owning the input key & its index
+func (i *shardedIntern[T]) shard(input T) (uint64, *internShard[T]) {
+	index := i.hash(input) % uint64(len(i.shards))
+	return index, &i.shards[index]
+}
+
+// local returns the index of the unique id inside its shard's values
+func (i *shardedIntern[T]) local(uniqueID uint64) uint64 {
+	return (uniqueID - 1) / uint64(len(i.shards))
+}
+
+// insert adds the input to the shard, the caller must hold the shard's write lock.
+func (i *shardedIntern[T]) insert(index uint64, shard *internShard[T], input T) uint64 {
+	if uniqueID, ok := shard.keys[input]; ok {
+		return uniqueID
+	}
+
+	uniqueID := uint64(len(shard.values))*uint64(len(i.shards)) + index + 1
+	shard.keys[input] = uniqueID
+	shard.values = append(shard.values, input)
+	return uniqueID
+}
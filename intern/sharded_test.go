@@ -0,0 +1,93 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"hash/maphash"
+	"sync"
+	"testing"
+)
+
+func TestShardedIntern(t *testing.T) {
+	i := NewSharded[string](4, stringHash(maphash.MakeSeed()))
+	require.Zero(t, i.Len())
+
+	output, ok := i.Value(0)
+	require.False(t, ok)
+	require.Zero(t, output)
+
+	index := i.Insert("value")
+	require.NotZero(t, index)
+
+	output, ok = i.Value(index)
+	require.True(t, ok)
+	require.EqualValues(t, output, "value")
+
+	index2 := i.Insert("value")
+	require.EqualValues(t, index, index2)
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	_, ok = i.Value(index)
+	require.False(t, ok)
+
+	inputs := randomStringInputs(1_00)
+	unique := make(map[string]string, len(inputs))
+	for _, k := range inputs {
+		unique[k] = k
+	}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, input := range inputs {
+				assert.EqualValues(t, i.Deduplicate(input), input)
+			}
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, i.Len(), len(unique))
+
+	keys := map[uint64]string{}
+	for _, input := range inputs {
+		uniqueID := i.Insert(input)
+		output, ok := i.Value(uniqueID)
+		require.True(t, ok)
+		require.EqualValues(t, output, input)
+		keys[uniqueID] = input
+	}
+	require.Len(t, keys, len(unique))
+}
+
+func BenchmarkShardedIntern(b *testing.B) {
+	inputs := randomStringInputs(10_000)
+
+	i := NewSharded[string](0, stringHash(maphash.MakeSeed()))
+
+	rows := make(chan string)
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range rows {
+				i.Deduplicate(row)
+			}
+		}()
+	}
+
+	for index := 0; index < b.N; index++ {
+		rows <- inputs[index%len(inputs)]
+	}
+	close(rows)
+	wg.Wait()
+}
+
+func stringHash(seed maphash.Seed) func(string) uint64 {
+	return func(input string) uint64 {
+		return maphash.String(seed, input)
+	}
+}
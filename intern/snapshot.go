@@ -0,0 +1,513 @@
+package intern
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"slices"
+)
+
+// Snapshot format, all integers are unsigned varints unless noted:
+//
+//	magic "GINT" | version (1 byte) | counter | count | count * (id | len | value bytes) | crc32c of everything before (4 bytes, little endian)
+const (
+	snapshotMagic   = "GINT"
+	snapshotVersion = 1
+)
+
+var (
+	// ErrSnapshotUnsupported is returned when the intern table or value type cannot be snapshot
+	ErrSnapshotUnsupported = errors.New("intern: snapshot not supported")
+	// ErrSnapshotVersion is returned when the snapshot header is not recognised
+	ErrSnapshotVersion = errors.New("intern: unknown snapshot version")
+	// ErrSnapshotChecksum is returned when the snapshot checksum does not match its contents
+	ErrSnapshotChecksum = errors.New("intern: snapshot checksum mismatch")
+	// ErrSnapshotCorrupt is returned when the snapshot entries are not a valid intern table
+	ErrSnapshotCorrupt = errors.New("intern: corrupt snapshot")
+)
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Codec encodes & decodes interned values for snapshots
+type Codec[T any] interface {
+	// AppendBinary appends the encoded value to dst & returns the extended buffer
+	AppendBinary(dst []byte, value T) ([]byte, error)
+	// DecodeBinary decodes a value previously encoded by AppendBinary
+	DecodeBinary(src []byte) (T, error)
+}
+
+// snapshotter is implemented by intern tables that can be saved & restored with stable unique ids
+type snapshotter[T comparable] interface {
+	// snapshot returns the counter & the entries sorted by unique id
+	snapshot() (counter uint64, ids []uint64, values []T)
+	// restore replaces the contents of the table, the entries have already been validated
+	restore(counter uint64, ids []uint64, values []T)
+}
+
+var _ snapshotter[int] = &genericIntern[int]{}
+var _ snapshotter[int] = &safeGeneric[int]{}
+
+var _ encoding.BinaryMarshaler = &genericIntern[int]{}
+var _ encoding.BinaryUnmarshaler = &genericIntern[int]{}
+var _ io.WriterTo = &genericIntern[int]{}
+var _ io.ReaderFrom = &genericIntern[int]{}
+var _ encoding.BinaryMarshaler = &safeGeneric[int]{}
+var _ encoding.BinaryUnmarshaler = &safeGeneric[int]{}
+var _ io.WriterTo = &safeGeneric[int]{}
+var _ io.ReaderFrom = &safeGeneric[int]{}
+
+// DefaultCodec returns the Codec used by MarshalBinary & friends.
+// It supports strings, integers, floats, bools & types implementing encoding.BinaryMarshaler & encoding.BinaryUnmarshaler (on *T).
+func DefaultCodec[T any]() (Codec[T], error) {
+	var t T
+	switch any(&t).(type) {
+	case *string, *bool, *float32, *float64,
+		*int, *int8, *int16, *int32, *int64,
+		*uint, *uint8, *uint16, *uint32, *uint64, *uintptr:
+		return defaultCodec[T]{}, nil
+	}
+
+	_, marshaler := any(t).(encoding.BinaryMarshaler)
+	_, unmarshaler := any(&t).(encoding.BinaryUnmarshaler)
+	if !marshaler || !unmarshaler {
+		return nil, fmt.Errorf("%w: no default codec for %T", ErrSnapshotUnsupported, t)
+	}
+	return defaultCodec[T]{}, nil
+}
+
+// defaultCodec implements the Codec interface for the types accepted by DefaultCodec
+type defaultCodec[T any] struct{}
+
+func (defaultCodec[T]) AppendBinary(dst []byte, value T) ([]byte, error) {
+	switch v := any(value).(type) {
+	case string:
+		return append(dst, v...), nil
+	case bool:
+		if v {
+			return append(dst, 1), nil
+		}
+		return append(dst, 0), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(dst, math.Float32bits(v)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(dst, math.Float64bits(v)), nil
+	case int:
+		return binary.AppendVarint(dst, int64(v)), nil
+	case int8:
+		return binary.AppendVarint(dst, int64(v)), nil
+	case int16:
+		return binary.AppendVarint(dst, int64(v)), nil
+	case int32:
+		return binary.AppendVarint(dst, int64(v)), nil
+	case int64:
+		return binary.AppendVarint(dst, v), nil
+	case uint:
+		return binary.AppendUvarint(dst, uint64(v)), nil
+	case uint8:
+		return binary.AppendUvarint(dst, uint64(v)), nil
+	case uint16:
+		return binary.AppendUvarint(dst, uint64(v)), nil
+	case uint32:
+		return binary.AppendUvarint(dst, uint64(v)), nil
+	case uint64:
+		return binary.AppendUvarint(dst, v), nil
+	case uintptr:
+		return binary.AppendUvarint(dst, uint64(v)), nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return dst, err
+		}
+		return append(dst, data...), nil
+	}
+	return dst, fmt.Errorf("%w: no default codec for %T", ErrSnapshotUnsupported, value)
+}
+
+func (defaultCodec[T]) DecodeBinary(src []byte) (output T, err error) {
+	switch p := any(&output).(type) {
+	case *string:
+		*p = string(src)
+	case *bool:
+		if len(src) != 1 || src[0] > 1 {
+			return output, ErrSnapshotCorrupt
+		}
+		*p = src[0] == 1
+	case *float32:
+		if len(src) != 4 {
+			return output, ErrSnapshotCorrupt
+		}
+		*p = math.Float32frombits(binary.LittleEndian.Uint32(src))
+	case *float64:
+		if len(src) != 8 {
+			return output, ErrSnapshotCorrupt
+		}
+		*p = math.Float64frombits(binary.LittleEndian.Uint64(src))
+	case *int:
+		*p, err = decodeVarint[int](src, math.MinInt, math.MaxInt)
+	case *int8:
+		*p, err = decodeVarint[int8](src, math.MinInt8, math.MaxInt8)
+	case *int16:
+		*p, err = decodeVarint[int16](src, math.MinInt16, math.MaxInt16)
+	case *int32:
+		*p, err = decodeVarint[int32](src, math.MinInt32, math.MaxInt32)
+	case *int64:
+		*p, err = decodeVarint[int64](src, math.MinInt64, math.MaxInt64)
+	case *uint:
+		*p, err = decodeUvarint[uint](src, math.MaxUint)
+	case *uint8:
+		*p, err = decodeUvarint[uint8](src, math.MaxUint8)
+	case *uint16:
+		*p, err = decodeUvarint[uint16](src, math.MaxUint16)
+	case *uint32:
+		*p, err = decodeUvarint[uint32](src, math.MaxUint32)
+	case *uint64:
+		*p, err = decodeUvarint[uint64](src, math.MaxUint64)
+	case *uintptr:
+		*p, err = decodeUvarint[uintptr](src, math.MaxUint)
+	case encoding.BinaryUnmarshaler:
+		err = p.UnmarshalBinary(src)
+	default:
+		err = fmt.Errorf("%w: no default codec for %T", ErrSnapshotUnsupported, output)
+	}
+	return output, err
+}
+
+func decodeVarint[I ~int | ~int8 | ~int16 | ~int32 | ~int64](src []byte, minValue, maxValue int64) (I, error) {
+	value, n := binary.Varint(src)
+	if n != len(src) || value < minValue || value > maxValue {
+		return 0, ErrSnapshotCorrupt
+	}
+	return I(value), nil
+}
+
+func decodeUvarint[I ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](src []byte, maxValue uint64) (I, error) {
+	value, n := binary.Uvarint(src)
+	if n != len(src) || value > maxValue {
+		return 0, ErrSnapshotCorrupt
+	}
+	return I(value), nil
+}
+
+// WriteSnapshot writes the intern table to w using the codec, & returns the number of bytes written.
+// The intern table must have been created by New or NewSafe.
+func WriteSnapshot[T comparable](w io.Writer, intern GenericIntern[T], codec Codec[T]) (int64, error) {
+	source, ok := intern.(snapshotter[T])
+	if !ok {
+		return 0, fmt.Errorf("%w: %T", ErrSnapshotUnsupported, intern)
+	}
+	counter, ids, values := source.snapshot()
+
+	output := &snapshotWriter{w: w, crc: crc32.New(snapshotTable)}
+	buf := append([]byte(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, counter)
+	buf = binary.AppendUvarint(buf, uint64(len(ids)))
+	if err := output.write(buf); err != nil {
+		return output.n, err
+	}
+
+	var scratch []byte
+	for index, uniqueID := range ids {
+		var err error
+		if scratch, err = codec.AppendBinary(scratch[:0], values[index]); err != nil {
+			return output.n, err
+		}
+		buf = binary.AppendUvarint(buf[:0], uniqueID)
+		buf = binary.AppendUvarint(buf, uint64(len(scratch)))
+		buf = append(buf, scratch...)
+		if err = output.write(buf); err != nil {
+			return output.n, err
+		}
+	}
+
+	buf = binary.LittleEndian.AppendUint32(buf[:0], output.crc.Sum32())
+	return output.n, output.write(buf)
+}
+
+// ReadSnapshot replaces the contents of the intern table with a snapshot read from r using the codec,
+// & returns the number of bytes read. The intern table is left unchanged if an error is returned.
+// The intern table must have been created by New or NewSafe.
+//
+// If r does not implement io.ByteReader, then it is buffered & ReadSnapshot may read past the end of the snapshot.
+func ReadSnapshot[T comparable](r io.Reader, intern GenericIntern[T], codec Codec[T]) (int64, error) {
+	target, ok := intern.(snapshotter[T])
+	if !ok {
+		return 0, fmt.Errorf("%w: %T", ErrSnapshotUnsupported, intern)
+	}
+
+	input := &snapshotReader{r: r}
+	if byteReader, ok := r.(io.ByteReader); ok {
+		input.byteReader = byteReader
+	} else {
+		buffered := bufio.NewReader(r)
+		input.r, input.byteReader = buffered, buffered
+	}
+
+	header, err := input.read(len(snapshotMagic) + 1)
+	if err != nil {
+		return input.n, err
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic || header[len(snapshotMagic)] != snapshotVersion {
+		return input.n, ErrSnapshotVersion
+	}
+
+	counter, err := input.uvarint()
+	if err != nil {
+		return input.n, err
+	}
+	count, err := input.uvarint()
+	if err != nil {
+		return input.n, err
+	}
+	if count > counter {
+		return input.n, ErrSnapshotCorrupt
+	}
+
+	ids := make([]uint64, 0, min(count, 1<<16))
+	values := make([]T, 0, min(count, 1<<16))
+	seen := make(map[T]struct{}, min(count, 1<<16))
+	for index := uint64(0); index < count; index++ {
+		uniqueID, err := input.uvarint()
+		if err != nil {
+			return input.n, err
+		}
+		if uniqueID == 0 || uniqueID > counter || (index > 0 && uniqueID <= ids[index-1]) {
+			return input.n, ErrSnapshotCorrupt
+		}
+
+		size, err := input.uvarint()
+		if err != nil {
+			return input.n, err
+		}
+		if size > math.MaxInt32 {
+			return input.n, ErrSnapshotCorrupt
+		}
+		data, err := input.read(int(size))
+		if err != nil {
+			return input.n, err
+		}
+		value, err := codec.DecodeBinary(data)
+		if err != nil {
+			return input.n, err
+		}
+		if _, ok := seen[value]; ok {
+			return input.n, ErrSnapshotCorrupt
+		}
+		seen[value] = struct{}{}
+
+		ids = append(ids, uniqueID)
+		values = append(values, value)
+	}
+
+	// the counter is the last unique id issued, so it is the last id of the snapshot
+	if (count == 0 && counter != 0) || (count > 0 && ids[count-1] != counter) {
+		return input.n, ErrSnapshotCorrupt
+	}
+
+	sum := input.crc
+	trailer, err := input.read(4)
+	if err != nil {
+		return input.n, err
+	}
+	if binary.LittleEndian.Uint32(trailer) != sum {
+		return input.n, ErrSnapshotChecksum
+	}
+
+	target.restore(counter, ids, values)
+	return input.n, nil
+}
+
+// snapshotWriter counts & checksums the bytes written to w
+type snapshotWriter struct {
+	w   io.Writer
+	crc hash.Hash32
+	n   int64
+}
+
+func (w *snapshotWriter) write(p []byte) error {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.crc.Write(p[:n])
+	return err
+}
+
+// snapshotReader counts & checksums the bytes read from r, byteReader reads from the same stream as r
+type snapshotReader struct {
+	r          io.Reader
+	byteReader io.ByteReader
+	crc        uint32
+	n          int64
+	buf        []byte
+	varint     [binary.MaxVarintLen64]byte
+}
+
+func (r *snapshotReader) uvarint() (uint64, error) {
+	buf := r.varint[:]
+	for index := range buf {
+		b, err := r.byteReader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		buf[index] = b
+		if b < 0x80 {
+			r.n += int64(index + 1)
+			r.crc = crc32.Update(r.crc, snapshotTable, buf[:index+1])
+			value, n := binary.Uvarint(buf[:index+1])
+			if n <= 0 {
+				return 0, ErrSnapshotCorrupt
+			}
+			return value, nil
+		}
+	}
+	return 0, ErrSnapshotCorrupt
+}
+
+// read returns the next size bytes, the buffer is only valid until the next call.
+// The bytes are read in chunks, so a corrupt size fails at the end of the stream instead of allocating it upfront.
+func (r *snapshotReader) read(size int) ([]byte, error) {
+	r.buf = r.buf[:0]
+	for len(r.buf) < size {
+		start := len(r.buf)
+		r.buf = slices.Grow(r.buf, min(size-start, 1<<16))
+		n, err := io.ReadFull(r.r, r.buf[start:start+min(size-start, 1<<16)])
+		r.buf = r.buf[:start+n]
+		r.n += int64(n)
+		r.crc = crc32.Update(r.crc, snapshotTable, r.buf[start:])
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return r.buf, err
+		}
+	}
+	return r.buf, nil
+}
+
+func (i *genericIntern[T]) snapshot() (uint64, []uint64, []T) {
+	ids := make([]uint64, 0, len(i.values))
+	for uniqueID := range i.values {
+		ids = append(ids, uniqueID)
+	}
+	slices.Sort(ids)
+
+	values := make([]T, len(ids))
+	for index, uniqueID := range ids {
+		values[index] = i.values[uniqueID]
+	}
+	return i.counter, ids, values
+}
+
+func (i *genericIntern[T]) restore(counter uint64, ids []uint64, values []T) {
+	i.Clear()
+	var bytes int64
+	for index, uniqueID := range ids {
+		i.keys[values[index]] = uniqueID
+		i.values[uniqueID] = values[index]
+		bytes += entrySize(i.sizer, values[index])
+	}
+	i.counter = counter
+	i.stats.add(int64(len(ids)), bytes)
+}
+
+// MarshalBinary encodes the intern table with DefaultCodec
+func (i *genericIntern[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary[T](i)
+}
+
+// UnmarshalBinary replaces the intern table with one encoded by MarshalBinary
+func (i *genericIntern[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary[T](i, data)
+}
+
+// WriteTo writes the intern table to w with DefaultCodec
+func (i *genericIntern[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeTo[T](w, i)
+}
+
+// ReadFrom replaces the intern table with one read from r with DefaultCodec
+func (i *genericIntern[T]) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom[T](r, i)
+}
+
+func (i *safeGeneric[T]) snapshot() (uint64, []uint64, []T) {
+	i.RWMutex.RLock()
+	defer i.RWMutex.RUnlock()
+	return i.intern.(snapshotter[T]).snapshot()
+}
+
+func (i *safeGeneric[T]) restore(counter uint64, ids []uint64, values []T) {
+	i.Lock()
+	i.intern.(snapshotter[T]).restore(counter, ids, values)
+	i.Unlock()
+}
+
+// MarshalBinary encodes the intern table with DefaultCodec
+func (i *safeGeneric[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary[T](i)
+}
+
+// UnmarshalBinary replaces the intern table with one encoded by MarshalBinary
+func (i *safeGeneric[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary[T](i, data)
+}
+
+// WriteTo writes the intern table to w with DefaultCodec
+func (i *safeGeneric[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeTo[T](w, i)
+}
+
+// ReadFrom replaces the intern table with one read from r with DefaultCodec
+func (i *safeGeneric[T]) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom[T](r, i)
+}
+
+func marshalBinary[T comparable](intern GenericIntern[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := writeTo[T](&buf, intern); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalBinary[T comparable](intern GenericIntern[T], data []byte) error {
+	codec, err := DefaultCodec[T]()
+	if err != nil {
+		return err
+	}
+	// Decode into a scratch table first, so trailing garbage leaves the intern table unchanged
+	input := bytes.NewReader(data)
+	scratch := New[T]()
+	if _, err = ReadSnapshot[T](input, scratch, codec); err != nil {
+		return err
+	}
+	if input.Len() != 0 {
+		return ErrSnapshotCorrupt
+	}
+	intern.(snapshotter[T]).restore(scratch.(snapshotter[T]).snapshot())
+	return nil
+}
+
+func writeTo[T comparable](w io.Writer, intern GenericIntern[T]) (int64, error) {
+	codec, err := DefaultCodec[T]()
+	if err != nil {
+		return 0, err
+	}
+	return WriteSnapshot[T](w, intern, codec)
+}
+
+func readFrom[T comparable](r io.Reader, intern GenericIntern[T]) (int64, error) {
+	codec, err := DefaultCodec[T]()
+	if err != nil {
+		return 0, err
+	}
+	return ReadSnapshot[T](r, intern, codec)
+}
@@ -0,0 +1,140 @@
+package intern
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		i := New[string]()
+		inputs := randomStringInputs(1_00)
+		ids := make([]uint64, len(inputs))
+		for index, input := range inputs {
+			ids[index] = i.Insert(input)
+		}
+
+		data, err := i.(*genericIntern[string]).MarshalBinary()
+		require.NoError(t, err)
+
+		restored := New[string]()
+		restored.Insert("stale")
+		require.NoError(t, restored.(*genericIntern[string]).UnmarshalBinary(data))
+		require.EqualValues(t, i.Len(), restored.Len())
+		for index, input := range inputs {
+			output, ok := restored.Value(ids[index])
+			require.True(t, ok)
+			require.EqualValues(t, input, output)
+			require.EqualValues(t, ids[index], restored.Insert(input))
+		}
+		require.EqualValues(t, i.Insert("new value"), restored.Insert("new value"))
+	})
+
+	t.Run("int stream", func(t *testing.T) {
+		i := NewSafe[int]()
+		for _, input := range []int{-1, 0, 1, 1 << 40} {
+			i.Insert(input)
+		}
+
+		var buf bytes.Buffer
+		written, err := i.(io.WriterTo).WriteTo(&buf)
+		require.NoError(t, err)
+		require.EqualValues(t, buf.Len(), written)
+		buf.WriteString("trailing data")
+
+		restored := NewSafe[int]()
+		read, err := restored.(io.ReaderFrom).ReadFrom(&buf)
+		require.NoError(t, err)
+		require.EqualValues(t, written, read)
+		require.EqualValues(t, "trailing data", buf.String())
+
+		for uniqueID, input := range []int{-1, 0, 1, 1 << 40} {
+			output, ok := restored.Value(uint64(uniqueID + 1))
+			require.True(t, ok)
+			require.EqualValues(t, input, output)
+		}
+	})
+
+	t.Run("binary marshaler", func(t *testing.T) {
+		i := New[time.Time]()
+		now := time.Now().UTC().Truncate(time.Second)
+		uniqueID := i.Insert(now)
+
+		data, err := i.(*genericIntern[time.Time]).MarshalBinary()
+		require.NoError(t, err)
+
+		restored := New[time.Time]()
+		require.NoError(t, restored.(*genericIntern[time.Time]).UnmarshalBinary(data))
+		output, ok := restored.Value(uniqueID)
+		require.True(t, ok)
+		require.True(t, now.Equal(output))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		i := New[string]()
+		i.Insert("value")
+		data, err := i.(*genericIntern[string]).MarshalBinary()
+		require.NoError(t, err)
+
+		restored := New[string]()
+		restored.Insert("unchanged")
+		target := restored.(*genericIntern[string])
+
+		corrupt := bytes.Clone(data)
+		corrupt[len(corrupt)-5] ^= 0xff
+		require.ErrorIs(t, target.UnmarshalBinary(corrupt), ErrSnapshotChecksum)
+
+		corrupt = bytes.Clone(data)
+		corrupt[len(snapshotMagic)] = snapshotVersion + 1
+		require.ErrorIs(t, target.UnmarshalBinary(corrupt), ErrSnapshotVersion)
+
+		require.ErrorIs(t, target.UnmarshalBinary(data[:len(data)-1]), io.ErrUnexpectedEOF)
+		require.ErrorIs(t, target.UnmarshalBinary(append(bytes.Clone(data), 0)), ErrSnapshotCorrupt)
+
+		oversized := append([]byte(snapshotMagic), snapshotVersion, 1, 1, 1)
+		oversized = binary.AppendUvarint(oversized, math.MaxInt32+1)
+		require.ErrorIs(t, target.UnmarshalBinary(append(oversized, "value"...)), ErrSnapshotCorrupt)
+
+		// a checksummed snapshot whose counter is past its last unique id
+		inflated := append([]byte(snapshotMagic), snapshotVersion)
+		inflated = binary.AppendUvarint(inflated, 1<<40)
+		inflated = append(inflated, 1, 1, 5)
+		inflated = append(inflated, "value"...)
+		inflated = binary.LittleEndian.AppendUint32(inflated, crc32.Checksum(inflated, snapshotTable))
+		require.ErrorIs(t, target.UnmarshalBinary(inflated), ErrSnapshotCorrupt)
+
+		output, ok := restored.Value(1)
+		require.True(t, ok)
+		require.EqualValues(t, "unchanged", output)
+
+		_, err = New[struct{ A int }]().(*genericIntern[struct{ A int }]).MarshalBinary()
+		require.ErrorIs(t, err, ErrSnapshotUnsupported)
+
+		codec, err := DefaultCodec[string]()
+		require.NoError(t, err)
+		_, err = WriteSnapshot[string](io.Discard, NewBounded[string](1, LRU, nil), codec)
+		require.ErrorIs(t, err, ErrSnapshotUnsupported)
+	})
+}
+
+func TestSnapshot_allocations(t *testing.T) {
+	i := New[string]()
+	for _, input := range randomStringInputs(1_000) {
+		i.Insert(input)
+	}
+	data, err := i.(*genericIntern[string]).MarshalBinary()
+	require.NoError(t, err)
+
+	restored := New[string]().(*genericIntern[string])
+	allocs := testing.AllocsPerRun(10, func() {
+		require.NoError(t, restored.UnmarshalBinary(data))
+	})
+	// a few allocations per decoded string for the value & the maps, rather than one per byte
+	require.Less(t, allocs, float64(4*restored.Len()))
+}
@@ -0,0 +1,129 @@
+package intern
+
+import (
+	"cmp"
+	"log"
+	"slices"
+)
+
+// SortedIntern is a read-only intern table whose unique ids follow the order of the values: `id(a) < id(b)` iff `a < b`.
+// Range predicates on dictionary-encoded data can then compare unique ids instead of decoding values.
+// Insert & Deduplicate return the existing entry for values present when the table was built, & panic for unseen values.
+// Clear panics.
+type SortedIntern[T comparable] interface {
+	GenericIntern[T]
+	Lookup[T]
+	Ranger[T]
+
+	// LowerBound returns the unique id of the first value >= input, or Len()+1 if every value is < input.
+	LowerBound(input T) (uniqueID uint64)
+
+	// UpperBound returns the unique id of the first value > input, or Len()+1 if every value is <= input.
+	UpperBound(input T) (uniqueID uint64)
+}
+
+var _ SortedIntern[int] = &sortedIntern[int]{}
+
+// sortedIntern implements the SortedIntern interface with a sorted slice of values indexed by `uniqueID-1`
+type sortedIntern[T comparable] struct {
+	values  []T
+	compare func(a, b T) int
+}
+
+// NewSorted creates a new SortedIntern[T] instance holding the distinct inputs in ascending order
+func NewSorted[T cmp.Ordered](inputs []T) SortedIntern[T] {
+	return NewSortedFunc(inputs, cmp.Compare[T])
+}
+
+// NewSortedFunc creates a new SortedIntern[T] instance holding the distinct inputs in the order defined by compare.
+// compare must return 0 if & only if the values are equal.
+func NewSortedFunc[T comparable](inputs []T, compare func(a, b T) int) SortedIntern[T] {
+	if nil == compare {
+		log.Panic("compare is required for NewSortedFunc")
+	}
+	values := slices.Clone(inputs)
+	slices.SortFunc(values, compare)
+	values = slices.CompactFunc(values, func(a, b T) bool { return compare(a, b) == 0 })
+	return &sortedIntern[T]{values: slices.Clip(values), compare: compare}
+}
+
+// Sort builds a SortedIntern[T] from the entries of the src intern table, & returns the remap table from the
+// src unique ids to the sorted unique ids (see Merge & Remap).
+func Sort[T comparable](src Ranger[T], compare func(a, b T) int) (SortedIntern[T], []uint64) {
+	var values []T
+	src.Range(func(_ uint64, value T) bool {
+		values = append(values, value)
+		return true
+	})
+	output := NewSortedFunc(values, compare)
+
+	var remap []uint64
+	src.Range(func(uniqueID uint64, value T) bool {
+		if uniqueID >= uint64(len(remap)) {
+			remap = append(remap, make([]uint64, uniqueID+1-uint64(len(remap)))...)
+		}
+		remap[uniqueID], _ = output.ID(value)
+		return true
+	})
+	return output, remap
+}
+
+func (i *sortedIntern[T]) Deduplicate(input T) T {
+	return i.values[i.Insert(input)-1]
+}
+
+func (i *sortedIntern[T]) Insert(input T) uint64 {
+	uniqueID, ok := i.ID(input)
+	if !ok {
+		log.Panicf("cannot insert %v into a sorted intern", input)
+	}
+	return uniqueID
+}
+
+func (i *sortedIntern[T]) ID(input T) (uint64, bool) {
+	index, ok := slices.BinarySearchFunc(i.values, input, i.compare)
+	if !ok {
+		return 0, false
+	}
+	return uint64(index + 1), true
+}
+
+func (i *sortedIntern[T]) LowerBound(input T) uint64 {
+	index, _ := slices.BinarySearchFunc(i.values, input, i.compare)
+	return uint64(index + 1)
+}
+
+func (i *sortedIntern[T]) UpperBound(input T) uint64 {
+	index, found := slices.BinarySearchFunc(i.values, input, i.compare)
+	if found {
+		index++
+	}
+	return uint64(index + 1)
+}
+
+func (i *sortedIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	if uniqueID == 0 || uniqueID > uint64(len(i.values)) {
+		return output, false
+	}
+	return i.values[uniqueID-1], true
+}
+
+func (i *sortedIntern[T]) Len() int {
+	return len(i.values)
+}
+
+func (i *sortedIntern[T]) Clear() {
+	log.Panic("cannot clear a sorted intern")
+}
+
+func (i *sortedIntern[T]) Range(yield func(uniqueID uint64, value T) bool) {
+	for index, value := range i.values {
+		if !yield(uint64(index+1), value) {
+			return
+		}
+	}
+}
+
+func (i *sortedIntern[T]) All() func(yield func(uniqueID uint64, value T) bool) {
+	return i.Range
+}
@@ -0,0 +1,54 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSortedIntern(t *testing.T) {
+	i := NewSorted([]string{"delta", "alpha", "charlie", "alpha", "bravo"})
+	require.EqualValues(t, 4, i.Len())
+
+	for uniqueID, value := range []string{"alpha", "bravo", "charlie", "delta"} {
+		require.EqualValues(t, uniqueID+1, i.Insert(value))
+		output, ok := i.Value(uint64(uniqueID + 1))
+		require.True(t, ok)
+		require.EqualValues(t, value, output)
+	}
+	_, ok := i.ID("echo")
+	require.False(t, ok)
+	require.Panics(t, func() { i.Insert("echo") })
+	require.Panics(t, i.Clear)
+
+	require.EqualValues(t, 1, i.LowerBound("a"))
+	require.EqualValues(t, 2, i.LowerBound("bravo"))
+	require.EqualValues(t, 3, i.UpperBound("bravo"))
+	require.EqualValues(t, 3, i.LowerBound("bz"))
+	require.EqualValues(t, 3, i.UpperBound("bz"))
+	require.EqualValues(t, 5, i.LowerBound("echo"))
+	require.EqualValues(t, 5, i.UpperBound("delta"))
+
+	descending := NewSortedFunc([]string{"b", "a", "c", "b"}, func(a, b string) int {
+		return strings.Compare(b, a)
+	})
+	require.EqualValues(t, 3, descending.Len())
+	output, _ := descending.Value(1)
+	require.EqualValues(t, "c", output)
+	require.EqualValues(t, 2, descending.Insert("b"))
+	require.EqualValues(t, 3, descending.LowerBound("a"))
+}
+
+func TestSort(t *testing.T) {
+	src := New[int]()
+	ids := InsertAll(src, []int{30, 10, 20, 10}, nil)
+
+	sorted, remap := Sort(src.(Ranger[int]), func(a, b int) int { return a - b })
+	require.EqualValues(t, []uint64{0, 3, 1, 2}, remap)
+
+	require.NoError(t, Remap(ids, remap))
+	require.EqualValues(t, []uint64{3, 1, 2, 1}, ids)
+	outputs, err := Values[int](sorted, ids, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, []int{30, 10, 20, 10}, outputs)
+}
@@ -0,0 +1,106 @@
+package intern
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Stats is a point in time view of the counters of an intern table
+type Stats struct {
+	// Inserts is the number of calls to Insert or Deduplicate
+	Inserts uint64
+	// Hits is the number of inserts that found an existing value
+	Hits uint64
+	// Misses is the number of inserts that added a new value
+	Misses uint64
+	// Clears is the number of calls to Clear
+	Clears uint64
+	// Entries is the current number of interned values
+	Entries int64
+	// PeakEntries is the highest number of interned values seen since the table was created
+	PeakEntries int64
+	// Bytes is the estimated memory footprint of the interned values & their map entries
+	Bytes int64
+}
+
+// StatsProvider is implemented by intern tables that track Stats.
+// Reading the stats never takes the table lock.
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// Sizer estimates the number of bytes retained by a value
+type Sizer[T any] func(value T) int64
+
+var _ StatsProvider = &genericIntern[int]{}
+var _ StatsProvider = &safeGeneric[int]{}
+
+// DefaultSizer returns a Sizer that is exact for string kinds (header + bytes), & unsafe.Sizeof(T) for anything else.
+// It does not follow pointers.
+func DefaultSizer[T any]() Sizer[T] {
+	var t T
+	size := int64(unsafe.Sizeof(t))
+	if reflect.TypeOf(&t).Elem().Kind() == reflect.String {
+		return func(value T) int64 {
+			return size + int64(len(*(*string)(unsafe.Pointer(&value))))
+		}
+	}
+	return func(T) int64 {
+		return size
+	}
+}
+
+// internStats holds the atomic counters behind Stats
+type internStats struct {
+	inserts, hits, misses, clears atomic.Uint64
+	entries, peak, bytes          atomic.Int64
+}
+
+func (s *internStats) load() Stats {
+	// the struct fields are loaded in order, so PeakEntries is loaded after Entries
+	return Stats{
+		Inserts:     s.inserts.Load(),
+		Hits:        s.hits.Load(),
+		Misses:      s.misses.Load(),
+		Clears:      s.clears.Load(),
+		Entries:     s.entries.Load(),
+		PeakEntries: s.peak.Load(),
+		Bytes:       s.bytes.Load(),
+	}
+}
+
+// add records new entries, the caller must be the only writer.
+// The peak is raised before the entries are published, & load reads the peak last, so readers never see Entries > PeakEntries.
+func (s *internStats) add(entries, bytes int64) {
+	entries += s.entries.Load()
+	for {
+		peak := s.peak.Load()
+		if entries <= peak || s.peak.CompareAndSwap(peak, entries) {
+			break
+		}
+	}
+	s.entries.Store(entries)
+	s.bytes.Add(bytes)
+}
+
+// reset records a Clear, the caller must be the only writer
+func (s *internStats) reset() {
+	s.clears.Add(1)
+	s.entries.Store(0)
+	s.bytes.Store(0)
+}
+
+// entrySize estimates the bytes retained by one entry of a genericIntern:
+// the value, plus the key & value slots of both maps.
+func entrySize[T comparable](sizer Sizer[T], value T) int64 {
+	return sizer(value) + int64(unsafe.Sizeof(value)) + 2*int64(unsafe.Sizeof(uint64(0)))
+}
+
+func (i *genericIntern[T]) Stats() Stats {
+	return i.stats.load()
+}
+
+func (i *safeGeneric[T]) Stats() Stats {
+	return i.intern.(StatsProvider).Stats()
+}
@@ -0,0 +1,72 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestStats(t *testing.T) {
+	i := New[string]()
+	require.Zero(t, i.(StatsProvider).Stats())
+
+	i.Insert("value")
+	i.Insert("value")
+	i.Deduplicate("other")
+
+	stats := i.(StatsProvider).Stats()
+	require.EqualValues(t, 3, stats.Inserts)
+	require.EqualValues(t, 1, stats.Hits)
+	require.EqualValues(t, 2, stats.Misses)
+	require.EqualValues(t, 2, stats.Entries)
+	require.EqualValues(t, 2, stats.PeakEntries)
+	require.EqualValues(t, 2*(2*unsafe.Sizeof("")+16)+uintptr(len("value")+len("other")), stats.Bytes)
+
+	i.Clear()
+	stats = i.(StatsProvider).Stats()
+	require.EqualValues(t, 1, stats.Clears)
+	require.Zero(t, stats.Entries)
+	require.Zero(t, stats.Bytes)
+	require.EqualValues(t, 2, stats.PeakEntries)
+}
+
+func TestStats_sizer(t *testing.T) {
+	type name string
+	require.EqualValues(t, unsafe.Sizeof("")+4, DefaultSizer[name]()("abcd"))
+	require.EqualValues(t, 8, DefaultSizer[int64]()(123))
+
+	i := NewSafeSized[int](func(value int) int64 { return 100 })
+	i.Insert(1)
+	require.EqualValues(t, 100+unsafe.Sizeof(0)+16, i.(StatsProvider).Stats().Bytes)
+}
+
+func TestStats_safe(t *testing.T) {
+	i := NewSafe[string]()
+	inputs := randomStringInputs(1_00)
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, input := range inputs {
+				i.Deduplicate(input)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range inputs {
+				stats := i.(StatsProvider).Stats()
+				assert.LessOrEqual(t, stats.Entries, stats.PeakEntries)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := i.(StatsProvider).Stats()
+	require.EqualValues(t, 10*len(inputs), stats.Inserts)
+	require.EqualValues(t, stats.Inserts, stats.Hits+stats.Misses)
+	require.EqualValues(t, i.Len(), stats.Entries)
+}
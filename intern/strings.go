@@ -0,0 +1,94 @@
+package intern
+
+import (
+	"unsafe"
+)
+
+const (
+	// stringsChunkSize is the size of the arena chunks interned strings are copied into
+	stringsChunkSize = 64 << 10
+	// stringsLargeSize is the length above which a string gets its own allocation instead of an arena slot
+	stringsLargeSize = stringsChunkSize / 8
+)
+
+var _ GenericIntern[string] = &Strings{}
+var _ BatchIntern[string] = &Strings{}
+var _ Lookup[string] = &Strings{}
+var _ Ranger[string] = &Strings{}
+var _ StatsProvider = &Strings{}
+
+// Strings is a GenericIntern[string] specialized for strings parsed out of []byte buffers.
+// Lookups by []byte do not allocate on hits, & new values are copied into an internal arena on misses,
+// so interned strings never pin the caller's (possibly large) buffer.
+//
+// Strings is not safe for concurrent use.
+type Strings struct {
+	*genericIntern[string]
+	chunk []byte
+}
+
+// NewStrings creates a new Strings instance
+func NewStrings() *Strings {
+	return &Strings{genericIntern: New[string]().(*genericIntern[string])}
+}
+
+// Deduplicate returns the interned copy of the input, see GenericIntern.Deduplicate
+func (i *Strings) Deduplicate(input string) string {
+	return i.DeduplicateBytes(unsafe.Slice(unsafe.StringData(input), len(input)))
+}
+
+// Insert returns the unique id of the input, see GenericIntern.Insert
+func (i *Strings) Insert(input string) uint64 {
+	return i.InsertBytes(unsafe.Slice(unsafe.StringData(input), len(input)))
+}
+
+// DeduplicateBytes returns the interned string equal to the input, without allocating if it is already present.
+// The input is not retained.
+func (i *Strings) DeduplicateBytes(input []byte) string {
+	return i.values[i.InsertBytes(input)]
+}
+
+// InsertBytes returns the unique id of the string equal to the input, without allocating if it is already present.
+// The input is not retained.
+func (i *Strings) InsertBytes(input []byte) uint64 {
+	if uniqueId, ok := i.keys[string(input)]; ok {
+		i.stats.inserts.Add(1)
+		i.stats.hits.Add(1)
+		return uniqueId
+	}
+	return i.genericIntern.Insert(i.copy(input))
+}
+
+// InsertAll inserts every input & appends their unique ids to dst, see BatchIntern.InsertAll
+func (i *Strings) InsertAll(inputs []string, dst []uint64) []uint64 {
+	return insertAll[string](i, inputs, dst)
+}
+
+// DeduplicateAll replaces every input with its interned value, see BatchIntern.DeduplicateAll
+func (i *Strings) DeduplicateAll(inputs []string) {
+	deduplicateAll[string](i, inputs)
+}
+
+// Clear deletes the interned values & reset the counter back to 0.
+// The arena is released rather than reused, because previously interned strings may still be referenced.
+func (i *Strings) Clear() {
+	i.genericIntern.Clear()
+	i.chunk = nil
+}
+
+// copy copies the input into the arena & returns it as a string
+func (i *Strings) copy(input []byte) string {
+	if len(input) == 0 {
+		return ""
+	}
+	if len(input) > stringsLargeSize {
+		return string(input)
+	}
+	if len(input) > cap(i.chunk)-len(i.chunk) {
+		i.chunk = make([]byte, 0, stringsChunkSize)
+	}
+
+	start := len(i.chunk)
+	i.chunk = append(i.chunk, input...)
+	return unsafe.String(&i.chunk[start], len(input))
+}
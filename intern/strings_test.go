@@ -0,0 +1,66 @@
+package intern
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStrings(t *testing.T) {
+	i := NewStrings()
+	require.Zero(t, i.Len())
+
+	buffer := []byte("header value trailer")
+	index := i.InsertBytes(buffer[7:12])
+	require.NotZero(t, index)
+	require.EqualValues(t, index, i.Insert("value"))
+
+	output := i.DeduplicateBytes(buffer[7:12])
+	copy(buffer, bytes.Repeat([]byte{'x'}, len(buffer)))
+	require.EqualValues(t, "value", output)
+
+	output, ok := i.Value(index)
+	require.True(t, ok)
+	require.EqualValues(t, "value", output)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		i.DeduplicateBytes([]byte("value"))
+		i.InsertBytes([]byte("value"))
+	})
+	require.Zero(t, allocs)
+
+	large := bytes.Repeat([]byte{'y'}, stringsLargeSize+1)
+	require.EqualValues(t, string(large), i.DeduplicateBytes(large))
+	require.EqualValues(t, "", i.DeduplicateBytes(nil))
+
+	stats := i.Stats()
+	require.EqualValues(t, 3, stats.Misses)
+	require.EqualValues(t, 3, stats.Entries)
+
+	i.Clear()
+	require.Zero(t, i.Len())
+	require.EqualValues(t, "value", output)
+
+	inputs := randomStringInputs(1_00)
+	unique := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		unique[input] = input
+		require.EqualValues(t, input, i.Deduplicate(input))
+	}
+	require.EqualValues(t, len(unique), i.Len())
+}
+
+func BenchmarkStringsBytes(b *testing.B) {
+	inputs := randomStringInputs(10_000)
+	buffers := make([][]byte, len(inputs))
+	for index, input := range inputs {
+		buffers[index] = []byte(input)
+	}
+
+	i := NewStrings()
+
+	b.ReportAllocs()
+	for index := 0; index < b.N; index++ {
+		i.DeduplicateBytes(buffers[index%len(buffers)])
+	}
+}
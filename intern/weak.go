@@ -0,0 +1,231 @@
+package intern
+
+import (
+	"hash/maphash"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"unique"
+	"unsafe"
+	"weak"
+)
+
+// WeakIntern is a GenericIntern whose values are reclaimable by the garbage collector once unused.
+// Values are canonicalized with unique.Make, and the table only holds weak pointers to its entries,
+// so an entry (and its unique id) stays valid while it is in use:
+//   - for non-empty string kinds, while any string returned by Deduplicate, Value or a handle shares the canonical bytes.
+//   - for every other value, while the *WeakHandle returned by Make is reachable.
+//
+// Removal of unused entries is delayed until the garbage collector runs their cleanup,
+// & may never happen for short strings packed together with other values by the allocator.
+type WeakIntern[T comparable] interface {
+	GenericIntern[T]
+
+	// Make interns the input & returns a handle that keeps the entry & its unique id alive while it is reachable.
+	Make(input T) *WeakHandle[T]
+}
+
+// WeakHandle is a strong reference to an entry in a WeakIntern
+type WeakHandle[T comparable] struct {
+	handle   unique.Handle[T]
+	uniqueID uint64
+}
+
+// Value returns the canonical interned value
+func (h *WeakHandle[T]) Value() T {
+	return h.handle.Value()
+}
+
+// ID returns the unique id of the interned value
+func (h *WeakHandle[T]) ID() uint64 {
+	return h.uniqueID
+}
+
+// Handle returns the unique.Handle of the interned value
+func (h *WeakHandle[T]) Handle() unique.Handle[T] {
+	return h.handle
+}
+
+var _ WeakIntern[int] = &weakIntern[int]{}
+
+// weakIntern implements the WeakIntern interface on top of unique.Handle & weak.Pointer.
+// It is always safe for concurrent use, because cleanups of collected entries run on their own goroutine.
+//
+// A non-empty string entry weakly references its anchors, the bytes of a canonical string.
+// unique.Make allocates a new canonical string once its handles are unreachable, even if a string sharing the old bytes is still held,
+// so entries are found by hashing their value, & a string entry gains an anchor for every canonical string it outlives.
+// Every other entry weakly references its *WeakHandle, which Make hands out again while it is reachable.
+type weakIntern[T comparable] struct {
+	sync.Mutex
+	hashes  map[uint64][]uint64
+	values  map[uint64]*weakEntry[T]
+	counter uint64
+	seed    maphash.Seed
+	strings bool
+}
+
+// weakEntry is an entry of a weakIntern, it only references the canonical value weakly
+type weakEntry[T comparable] struct {
+	// anchors & length are only set for non-empty string kinds, the value would keep the anchors alive
+	anchors []weak.Pointer[byte]
+	length  int
+	// handle & value are set for every other entry
+	handle weak.Pointer[WeakHandle[T]]
+	value  T
+	hash   uint64
+}
+
+// weakCleanup identifies the anchor or handle to remove once it has been collected
+type weakCleanup[T comparable] struct {
+	anchor   weak.Pointer[byte]
+	handle   weak.Pointer[WeakHandle[T]]
+	uniqueID uint64
+}
+
+// NewWeak creates a new WeakIntern[T] instance
+func NewWeak[T comparable]() WeakIntern[T] {
+	return &weakIntern[T]{
+		hashes:  map[uint64][]uint64{},
+		values:  map[uint64]*weakEntry[T]{},
+		seed:    maphash.MakeSeed(),
+		strings: reflect.TypeFor[T]().Kind() == reflect.String,
+	}
+}
+
+func (i *weakIntern[T]) Deduplicate(input T) T {
+	return i.Make(input).Value()
+}
+
+func (i *weakIntern[T]) Insert(input T) uint64 {
+	return i.Make(input).ID()
+}
+
+func (i *weakIntern[T]) Make(input T) *WeakHandle[T] {
+	handle := unique.Make(input)
+	value := handle.Value()
+	hash := maphash.Comparable(i.seed, value)
+
+	var anchor *byte
+	var length int
+	if i.strings {
+		canonical := *(*string)(unsafe.Pointer(&value))
+		anchor, length = unsafe.StringData(canonical), len(canonical)
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	for _, uniqueID := range i.hashes[hash] {
+		entry := i.values[uniqueID]
+		if length == 0 {
+			if output := entry.handle.Value(); output != nil && entry.value == value {
+				return output
+			}
+			continue
+		}
+
+		if output, ok := i.value(entry); !ok || output != value {
+			continue
+		}
+		if pointer := weak.Make(anchor); !slices.Contains(entry.anchors, pointer) {
+			entry.anchors = append(entry.anchors, pointer)
+			runtime.AddCleanup(anchor, i.cleanup, weakCleanup[T]{anchor: pointer, uniqueID: uniqueID})
+		}
+		return &WeakHandle[T]{handle: handle, uniqueID: uniqueID}
+	}
+
+	i.counter++
+	output := &WeakHandle[T]{handle: handle, uniqueID: i.counter}
+	entry := &weakEntry[T]{length: length, hash: hash}
+	if length > 0 {
+		pointer := weak.Make(anchor)
+		entry.anchors = []weak.Pointer[byte]{pointer}
+		runtime.AddCleanup(anchor, i.cleanup, weakCleanup[T]{anchor: pointer, uniqueID: i.counter})
+	} else {
+		entry.handle, entry.value = weak.Make(output), value
+		runtime.AddCleanup(output, i.cleanup, weakCleanup[T]{handle: entry.handle, uniqueID: i.counter})
+	}
+	i.hashes[hash] = append(i.hashes[hash], i.counter)
+	i.values[i.counter] = entry
+	return output
+}
+
+func (i *weakIntern[T]) Value(uniqueID uint64) (output T, ok bool) {
+	i.Lock()
+	defer i.Unlock()
+
+	entry, ok := i.values[uniqueID]
+	if !ok {
+		return output, false
+	}
+	return i.value(entry)
+}
+
+// Len returns the number of entries that have not been cleaned up yet, some of which may already be unreachable.
+func (i *weakIntern[T]) Len() int {
+	i.Lock()
+	defer i.Unlock()
+	return len(i.values)
+}
+
+func (i *weakIntern[T]) Clear() {
+	i.Lock()
+	clear(i.hashes)
+	clear(i.values)
+	i.counter = 0
+	i.Unlock()
+}
+
+// value returns the entry's value if it is still reachable, the caller must hold the lock.
+func (i *weakIntern[T]) value(entry *weakEntry[T]) (output T, ok bool) {
+	if entry.length == 0 {
+		if entry.handle.Value() == nil {
+			return output, false
+		}
+		return entry.value, true
+	}
+
+	for _, pointer := range entry.anchors {
+		if anchor := pointer.Value(); anchor != nil {
+			canonical := unsafe.String(anchor, entry.length)
+			return *(*T)(unsafe.Pointer(&canonical)), true
+		}
+	}
+	return output, false
+}
+
+// cleanup removes a collected anchor or handle, & the entry once nothing references it.
+func (i *weakIntern[T]) cleanup(collected weakCleanup[T]) {
+	i.Lock()
+	defer i.Unlock()
+
+	entry, ok := i.values[collected.uniqueID]
+	if !ok {
+		// the entry was cleared
+		return
+	}
+	if entry.length == 0 {
+		if entry.handle != collected.handle {
+			return
+		}
+	} else {
+		if !slices.Contains(entry.anchors, collected.anchor) {
+			return
+		}
+		entry.anchors = slices.DeleteFunc(entry.anchors, func(pointer weak.Pointer[byte]) bool {
+			return pointer == collected.anchor
+		})
+		if len(entry.anchors) > 0 {
+			return
+		}
+	}
+
+	delete(i.values, collected.uniqueID)
+	i.hashes[entry.hash] = slices.DeleteFunc(i.hashes[entry.hash], func(uniqueID uint64) bool {
+		return uniqueID == collected.uniqueID
+	})
+	if len(i.hashes[entry.hash]) == 0 {
+		delete(i.hashes, entry.hash)
+	}
+}
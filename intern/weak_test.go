@@ -0,0 +1,94 @@
+package intern
+
+import (
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeakIntern(t *testing.T) {
+	i := NewWeak[string]()
+	require.Zero(t, i.Len())
+
+	output, ok := i.Value(0)
+	require.False(t, ok)
+	require.Zero(t, output)
+
+	handle := i.Make(strings.Repeat("value", 10))
+	require.NotZero(t, handle.ID())
+	require.EqualValues(t, handle.Value(), strings.Repeat("value", 10))
+	require.EqualValues(t, handle.ID(), i.Insert(strings.Repeat("value", 10)))
+	require.EqualValues(t, handle.ID(), i.Make(strings.Repeat("value", 10)).ID())
+
+	runtime.GC()
+	output, ok = i.Value(handle.ID())
+	require.True(t, ok)
+	require.EqualValues(t, output, strings.Repeat("value", 10))
+
+	uniqueID := handle.ID()
+	runtime.KeepAlive(handle)
+	handle, output = nil, ""
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		_, ok := i.Value(uniqueID)
+		return !ok && i.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	require.NotEqualValues(t, uniqueID, i.Insert(strings.Repeat("value", 10)))
+
+	i.Clear()
+	require.Zero(t, i.Len())
+}
+
+func TestWeakIntern_heldValue(t *testing.T) {
+	i := NewWeak[string]()
+
+	held := i.Deduplicate(strings.Repeat("value", 10))
+	uniqueID := i.Insert(strings.Repeat("value", 10))
+
+	runtime.GC()
+	runtime.GC()
+	output, ok := i.Value(uniqueID)
+	require.True(t, ok)
+	require.EqualValues(t, held, output)
+	require.EqualValues(t, uniqueID, i.Insert(strings.Repeat("value", 10)))
+	runtime.KeepAlive(held)
+
+	held, output = "", ""
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		_, ok := i.Value(uniqueID)
+		return !ok && i.Len() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestWeakIntern_heldHandle(t *testing.T) {
+	type point struct{ x, y, z [4]int64 }
+	i := NewWeak[point]()
+
+	handle := i.Make(point{x: [4]int64{1}})
+	uniqueID := i.Insert(point{x: [4]int64{1}})
+	require.EqualValues(t, handle.ID(), uniqueID)
+
+	runtime.GC()
+	runtime.GC()
+	output, ok := i.Value(uniqueID)
+	require.True(t, ok)
+	require.EqualValues(t, handle.Value(), output)
+	require.EqualValues(t, uniqueID, i.Insert(point{x: [4]int64{1}}))
+	require.Same(t, handle, i.Make(point{x: [4]int64{1}}))
+	runtime.KeepAlive(handle)
+	handle = nil
+
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		_, ok := i.Value(uniqueID)
+		return !ok && i.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	empty := NewWeak[struct{}]()
+	require.EqualValues(t, empty.Insert(struct{}{}), empty.Insert(struct{}{}))
+}